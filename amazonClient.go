@@ -13,233 +13,461 @@ import (
 	"net/http"
 	"net/url"
 	"os"
-	"strings"
+	"path/filepath"
+	"sync/atomic"
 	"time"
-
-	"github.com/fatih/structs"
-	"github.com/sirupsen/logrus"
 )
 
-// Creds holds amzMWS client credential data
+// Creds holds amzMWS client credential data.
+// For delegated developer access, AccessID and AccessKey belong to the developer,
+// Merchant is the seller's ID and AuthToken the MWSAuthToken the seller granted
 type Creds struct {
-	AccessID    string
-	AccessKey   string
-	CompanyName string
-	Merchant    string
+	AccessID    string `json:"access_id"`
+	AccessKey   string `json:"access_key"`
+	CompanyName string `json:"company_name"`
+	Merchant    string `json:"merchant_id"`
+	AuthToken   string `json:"mws_auth_token"`
 }
 
 // AmazonClient executes requests to the amzMWS api
 type AmazonClient struct {
-	credentials      Creds
-	Region           Region
-	SignatureMethod  string
+	credentials    CredentialsProvider
+	Region         Region
+	Signer         Signer
+	UserAgent      string
+	Logger         Logger
+	httpClient     *http.Client
+	retryPolicy    RetryPolicy
+	rateLimiter    *RateLimiter
+	rateLimiterSet bool
+	redaction      RedactionLevel
+	regionErr      error
+	skew           atomic.Int64
+	tracer         Tracer
+	metrics        Metrics
+
+	circuitBreakers map[string]*circuitBreaker
+	middleware      []Middleware
+	maxResponseSize int64
+
+	// SignatureMethod overrides the Method of a SignatureV2 Signer.
+	//
+	// Deprecated: use Signer
+	SignatureMethod string
+	// SignatureVersion is "2" for a SignatureV2 Signer, other versions are not supported.
+	//
+	// Deprecated: use Signer
 	SignatureVersion string
-	UserAgent        string
-	Logger           *logrus.Logger
+	// signatureMethod is the SignatureMethod set by NewAmazonClient, to tell caller changes apart
+	signatureMethod string
 }
 
 // NewAmazonClient creates and configures AmazonClient
-func NewAmazonClient(creds Creds, countryCode string, log *logrus.Logger) *AmazonClient {
+func NewAmazonClient(creds Creds, countryCode string, log Logger, opts ...Option) *AmazonClient {
 	h, _ := os.Hostname()
-	return &AmazonClient{
-		credentials:      creds,
-		SignatureVersion: "2",
-		SignatureMethod:  "HmacSHA256",
-		Region:           RegionByCountry(countryCode),
-		UserAgent:        fmt.Sprintf("%s/amazonAlert (Language=go; Host=%s)", creds.CompanyName, h),
-		Logger:           log,
+	// An unknown country leaves Region empty; the lookup error is returned by every call
+	region, regionErr := RegionByCountry(countryCode)
+	c := &AmazonClient{
+		credentials:     StaticCredentials(creds),
+		Signer:          SignatureV2{Method: "HmacSHA256"},
+		Region:          region,
+		UserAgent:       fmt.Sprintf("%s/amazonAlert (Language=go; Host=%s)", creds.CompanyName, h),
+		Logger:          log,
+		httpClient:      &http.Client{},
+		retryPolicy:     DefaultRetryPolicy,
+		maxResponseSize: DefaultMaxResponseSize,
+		regionErr:       regionErr,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if v2, ok := c.Signer.(SignatureV2); ok {
+		c.SignatureVersion, c.SignatureMethod = "2", v2.method()
+		c.signatureMethod = c.SignatureMethod
 	}
+	return c
 }
 
-func (c *AmazonClient) parseRequest(req *amazonRequest) (*http.Request, error) {
-	if c.credentials.AccessID == "" || c.credentials.AccessKey == "" || c.credentials.Merchant == "" || c.Region.Endpoint == "" {
+// parseRequest returns req signed with creds
+func (c *AmazonClient) parseRequest(ctx context.Context, req *amazonRequest, creds Creds) (*http.Request, error) {
+	if c.regionErr != nil && c.Region.Endpoint == "" {
+		return nil, c.regionErr
+	}
+	if creds.AccessID == "" || creds.AccessKey == "" || creds.Merchant == "" || c.Region.Endpoint == "" {
 		err := errors.New("Incomplete Request")
 		return nil, err
 	}
 
-	req.params.Add("SellerId", c.credentials.Merchant)
-	req.params.Add("AWSAccessKeyId", c.credentials.AccessID)
-	req.params.Add("MarketplaceId.Id.1", c.Region.MarketPlaceID)
-	req.params.Add("SignatureMethod", c.SignatureMethod)
-	req.params.Add("SignatureVersion", c.SignatureVersion)
-	req.params.Add("Timestamp", XMLTimestamp(time.Now()))
-
-	stringToSign, err := c.stringToSign(req)
-	if err != nil {
+	// Sign a copy of the params, so req can be executed again or retried
+	params := copyValues(req.params)
+	params.Set("SellerId", creds.Merchant)
+	params.Set("AWSAccessKeyId", creds.AccessID)
+	if creds.AuthToken != "" {
+		params.Set("MWSAuthToken", creds.AuthToken)
+	}
+	if err := c.setMarketplaces(params, req); err != nil {
 		return nil, err
 	}
-	signature := Sign(stringToSign, []byte(c.credentials.AccessKey))
-	req.params.Add("Signature", signature)
+	now := c.now()
+	params.Set("Timestamp", XMLTimestamp(now))
 
 	url, err := url.Parse(req.endpoint)
 	if err != nil {
 		return nil, err
 	}
-	url.RawQuery = CanonicalizedQueryString(req.params)
+	url.RawQuery = CanonicalizedQueryString(params)
 
+	var body []byte
 	request, err := http.NewRequest(req.method, url.String(), nil)
 	if req.body != nil {
-		request, err = http.NewRequest(req.method, url.String(), req.body)
+		// Read from a fresh reader so the body can be sent again on retry
+		body = req.body.Bytes()
+		request, err = http.NewRequest(req.method, url.String(), bytes.NewReader(body))
+	}
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
 		request.Header.Add("Content-Type", "text/xml")
 	}
-
 	request.Header.Add("User-Agent", c.UserAgent)
-	return request, err
+
+	signer, err := c.signer()
+	if err != nil {
+		return nil, err
+	}
+	if err := signer.Sign(request, body, creds, now); err != nil {
+		return nil, err
+	}
+	return request, nil
 }
 
-func (c *AmazonClient) callAPI(ctx context.Context, req *amazonRequest) ([]byte, error) {
-	// Parse request params
-	request, err := c.parseRequest(req)
+// callAPI sends req and decodes its XML response into v
+func (c *AmazonClient) callAPI(ctx context.Context, req *amazonRequest, v interface{}) (header http.Header, err error) {
+	ctx, span := c.startSpan(ctx, req)
+	defer func() { span.end(err) }()
+	return c.decode(ctx, req, v)
+}
+
+// decode sends req and decodes the XML response straight from the body into v. A 200 response
+// holding an ErrorResponse is returned as an *APIError
+func (c *AmazonClient) decode(ctx context.Context, req *amazonRequest, v interface{}) (http.Header, error) {
+	resp, request, err := c.send(ctx, req)
 	if err != nil {
 		return nil, err
 	}
+	defer resp.Body.Close()
 
+	// Keep the start of the body for the debug log only when there is a Logger
+	var logged *cappedBuffer
+	var body io.Reader = &maxSizeReader{r: resp.Body, max: c.maxResponseSize}
 	if c.Logger != nil {
-		fmt.Println("REQUESTING: ", request.URL.String())
+		logged = &cappedBuffer{max: maxLoggedBody}
+		body = io.TeeReader(body, logged)
 	}
 
-	// Send request to amzMWS api
-	request.WithContext(ctx)
-	client := &http.Client{}
-	resp, err := client.Do(request)
+	err = decodeXML(body, req.operation(), v)
+	if logged != nil {
+		c.log(ctx, LevelDebug, "RESPONSE from Amazon callAPI", Fields{
+			FieldOperation: req.operation(),
+			FieldURL:       redactURL(request.URL, c.redaction),
+			FieldBody:      redactBody(logged.Bytes(), c.redaction),
+		})
+	}
 	if err != nil {
-		if c.Logger != nil {
-			c.Logger.WithFields(structs.Map(err)).WithField("Requested", request.URL.String()).
-				Error("FAILED Amazon callAPI: " + err.Error())
-		}
-		return nil, err
+		return nil, contextError(ctx, err)
 	}
+	return resp.Header, nil
+}
+
+// downloadReport writes the response body of req to path once its Content-MD5 header is verified
+func (c *AmazonClient) downloadReport(ctx context.Context, req *amazonRequest, path string) (err error) {
+	ctx, span := c.startSpan(ctx, req)
+	defer func() { span.end(err) }()
+
+	resp, _, err := c.send(ctx, req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
 
-	// Read response
-	bodyContents, _ := ioutil.ReadAll(resp.Body)
+	// Download to a temp file next to path, so a failed download never leaves a partial file at path
+	out, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
 	defer func() {
-		cerr := resp.Body.Close()
-		// Only overwrite the retured error if the original error was nil and an
-		// error occurred while closing the body.
-		if err == nil && cerr != nil {
-			err = cerr
+		if err != nil {
+			out.Close()
+			os.Remove(out.Name())
 		}
 	}()
 
-	// Check for http error
-	if resp.StatusCode != 200 {
-		return nil, fmt.Errorf(string(bodyContents))
+	// Write the body to file simultaneous with download stream, hashing it on the way
+	hash := md5.New()
+	n, err := io.Copy(io.MultiWriter(out, hash), resp.Body)
+	if c.metrics != nil {
+		c.metrics.ObserveDownload(req.operation(), n)
 	}
-
-	if c.Logger != nil {
-		c.Logger.WithField("Requested", request.URL.String()).
-			Debug("RESPONSE: ", string(bodyContents))
+	if err != nil {
+		return contextError(ctx, err)
 	}
 
-	return bodyContents, nil
-}
-
-func (c *AmazonClient) downloadReport(ctx context.Context, req *amazonRequest, filepath string) error {
-	// Parse request params
-	request, err := c.parseRequest(req)
-	if err != nil {
+	var sum [md5.Size]byte
+	copy(sum[:], hash.Sum(nil))
+	if err := verifyContentMD5(req.operation(), resp.Header, sum); err != nil {
 		return err
 	}
 
-	if c.Logger != nil {
-		c.Logger.Debug("REQUESTING: ", request.URL.String())
+	// CreateTemp creates the file readable by its owner only, os.Create's default is 0666 before umask
+	if err := out.Chmod(0644); err != nil {
+		return err
 	}
-
-	// Send request to amzMWS api
-	request.WithContext(ctx)
-	client := &http.Client{}
-	resp, err := client.Do(request)
-	if err != nil {
-		if c.Logger != nil {
-			c.Logger.WithFields(structs.Map(err)).WithField("Requested", request.URL.String()).
-				Error("FAILED Amazon callAPI: " + err.Error())
-		}
+	if err := out.Close(); err != nil {
 		return err
 	}
+	return os.Rename(out.Name(), path)
+}
 
-	// Check for http error
-	if resp.StatusCode != 200 {
-		// Read and return http error response
-		bodyContents, _ := ioutil.ReadAll(resp.Body)
-		return fmt.Errorf(string(bodyContents))
+// send signs and sends req, retrying failures according to the client's RetryPolicy.
+// A successful (200) response is returned with its body unread
+func (c *AmazonClient) send(ctx context.Context, req *amazonRequest) (*http.Response, *http.Request, error) {
+	operation := req.operation()
+	span := spanFromContext(ctx)
+	breaker := c.circuitBreakers[req.section]
+	handler := c.handler()
+	expiredRetried := false
+	var lastErr error
+	for attempt := 1; ; attempt++ {
+		// Fail fast while amzMWS is down. A circuit opened by the previous attempts keeps their error
+		done, err := breaker.allow(ctx)
+		if err != nil {
+			var openErr *CircuitOpenError
+			if lastErr != nil && errors.As(err, &openErr) {
+				openErr.Err = lastErr
+			}
+			return nil, nil, err
+		}
+
+		// Credentials are resolved on every attempt, so rotated keys and sellers take effect
+		creds, err := c.credentials.Credentials(ctx)
+		if err != nil {
+			done(err)
+			return nil, nil, err
+		}
+
+		// Block until the seller's quota for the operation allows another request
+		waitStart := time.Now()
+		if limiter := c.limiter(creds); limiter != nil {
+			if err := limiter.Wait(ctx, operation); err != nil {
+				done(err)
+				return nil, nil, err
+			}
+		}
+		span.attempt(time.Since(waitStart))
+
+		// Parse request params, re-signed with a fresh Timestamp on every attempt
+		request, err := c.parseRequest(ctx, req, creds)
+		if err != nil {
+			done(err)
+			return nil, nil, err
+		}
+
+		fields := Fields{
+			FieldOperation: operation,
+			FieldURL:       redactURL(request.URL, c.redaction),
+			FieldAttempt:   attempt,
+		}
+		c.log(ctx, LevelDebug, "REQUESTING Amazon callAPI", fields)
+
+		// Send request to amzMWS api through the middleware chain
+		start := time.Now()
+		resp, err := handler(ctx, &Call{
+			Operation: operation,
+			Section:   req.section,
+			Attempt:   attempt,
+			Params:    copyValues(req.params),
+			Request:   request,
+		})
+		if err == nil && resp == nil {
+			err = errors.New("amzMWS middleware returned neither response nor error")
+		}
+		duration := time.Since(start)
+		fields[FieldDuration] = duration
+		c.observeResponse(req, request, resp, duration)
+		if err == nil {
+			c.observeServerTime(ctx, resp.Header, start, time.Now())
+		}
+		if err != nil {
+			err = redactError(contextError(ctx, err), c.redaction)
+		} else if resp.StatusCode != 200 {
+			// Read and return http error response
+			bodyContents, _ := ioutil.ReadAll(io.LimitReader(resp.Body, maxLoggedBody))
+			resp.Body.Close()
+			apiErr := newAPIError(operation, resp.StatusCode, bodyContents)
+			fields[FieldStatus] = resp.StatusCode
+			fields[FieldRequestID] = apiErr.RequestID
+			err = apiErr
+			span.response(resp.StatusCode, apiErr.RequestID, err)
+			if c.metrics != nil && IsThrottled(err) {
+				c.metrics.ObserveThrottled(operation)
+			}
+		} else {
+			fields[FieldStatus] = resp.StatusCode
+			fields[FieldRequestID] = resp.Header.Get("x-mws-request-id")
+			span.response(resp.StatusCode, resp.Header.Get("x-mws-request-id"), nil)
+			c.log(ctx, LevelDebug, "COMPLETED Amazon callAPI", fields)
+			done(nil)
+			return resp, request, nil
+		}
+		done(err)
+		lastErr = err
+		fields[FieldError] = err.Error()
+
+		// Retry once straight away, with the Timestamp corrected by the newly measured clock skew
+		if IsRequestExpired(err) && !expiredRetried {
+			expiredRetried = true
+			fields[FieldClockSkew] = c.ClockSkew()
+			c.log(ctx, LevelWarn, "RETRYING expired Amazon callAPI", fields)
+			span.retry(0, err)
+			if c.metrics != nil {
+				c.metrics.ObserveRetry(operation)
+			}
+			continue
+		}
+
+		delay, retry := c.retryPolicy.Backoff(operation, attempt, err)
+		if !retry {
+			c.log(ctx, LevelError, "FAILED Amazon callAPI", fields)
+			return nil, nil, err
+		}
+		fields[FieldRetryDelay] = delay
+		c.log(ctx, LevelWarn, "RETRYING Amazon callAPI", fields)
+		span.retry(delay, err)
+		if c.metrics != nil {
+			c.metrics.ObserveRetry(operation)
+		}
+		if err := sleepContext(ctx, delay); err != nil {
+			return nil, nil, err
+		}
 	}
-	defer resp.Body.Close()
+}
+
+func (c *AmazonClient) submitFeed(ctx context.Context, r *SubmitFeedRequest, v interface{}) (header http.Header, err error) {
+	// The span covers encoding the feed as well as submitting it
+	ctx, span := c.startSpan(ctx, &r.amazonRequest)
+	defer func() { span.end(err) }()
 
-	// Create the file
-	out, err := os.Create(filepath)
+	req, err := c.prepareFeed(ctx, r)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	defer out.Close()
 
-	// Write the body to file simultaneous with download stream
-	_, err = io.Copy(out, resp.Body)
-	return err
+	// Submit feed, decode() logs client errors
+	return c.decode(ctx, req, v)
 }
 
-func (c *AmazonClient) submitFeed(ctx context.Context, r *SubmitFeedRequest) ([]byte, error) {
+// prepareFeed encodes the feed and returns a copy of the request with the feed as body and its MD5
+// sum attached. r is left unchanged, so it can be submitted concurrently or again after a credentials change
+func (c *AmazonClient) prepareFeed(ctx context.Context, r *SubmitFeedRequest) (*amazonRequest, error) {
+	if r.feed == nil {
+		return nil, errors.New("no feed content, use one of the SubmitFeedRequest feed methods")
+	}
+	// The feed is submitted on behalf of the (possibly delegated) seller the request is signed for
+	creds, err := c.credentials.Credentials(ctx)
+	if err != nil {
+		return nil, err
+	}
+	feed := *r.feed
+	header := feedHeader{}
+	if feed.Header != nil {
+		header = *feed.Header
+	}
+	header.MerchantIdentifier = creds.Merchant
+	feed.Header = &header
+	if len(feed.PriceMessages) > 0 {
+		if feed.PriceMessages, err = r.priceMessages(); err != nil {
+			return nil, err
+		}
+	}
+
 	// Encode feed into xml and attach body
 	teeBytes := new(bytes.Buffer)
 	teeBytes.Write([]byte(xml.Header))
-	err := xml.NewEncoder(teeBytes).Encode(r.feed)
+	err = xml.NewEncoder(teeBytes).Encode(&feed)
 	if err != nil {
-		if c.Logger != nil {
-			c.Logger.WithFields(structs.Map(err)).WithFields(structs.Map(r.feed)).
-				Error("FAILED xml encode during Amazon submitFeed: " + err.Error())
-		}
+		c.log(ctx, LevelError, "FAILED xml encode during Amazon submitFeed", Fields{
+			FieldOperation: r.operation(),
+			FieldError:     err.Error(),
+		})
 		return nil, err
 	}
 
 	// Use teeReader to read bytes for MD5, and simultaneously copy into request body
-	r.body = new(bytes.Buffer)
-	tee := io.TeeReader(teeBytes, r.body)
+	req := r.amazonRequest.clone()
+	req.body = new(bytes.Buffer)
+	tee := io.TeeReader(teeBytes, req.body)
 
 	// Calculate and attach MD5 sum of XML body
 	hash := md5.New()
 	if _, err := io.Copy(hash, tee); err != nil {
-		if c.Logger != nil {
-			c.Logger.WithFields(structs.Map(err)).WithFields(structs.Map(r.feed)).
-				Error("FAILED to copy into MD5 hash during Amazon submitFeed: " + err.Error())
-		}
+		c.log(ctx, LevelError, "FAILED to copy into MD5 hash during Amazon submitFeed", Fields{
+			FieldOperation: r.operation(),
+			FieldError:     err.Error(),
+		})
 		return nil, err
 	}
 	contMD5base64 := base64.StdEncoding.EncodeToString(hash.Sum(nil))
 
-	r.params["ContentMD5Value"] = []string{contMD5base64}
+	req.params["ContentMD5Value"] = []string{contMD5base64}
 
-	//fmt.Printf("\n\n[[[[%s]]]]\n\n", r.body.String())
+	return &req, nil
+}
 
-	// Submit feed
-	respBytes, err := r.client.callAPI(ctx, &r.amazonRequest)
-	if err != nil {
-		// callAPI() logs client errors
-		return nil, err
+// setMarketplaces adds the request's marketplaces, or the client's own marketplace by default,
+// in the param shape of the request's API section
+func (c *AmazonClient) setMarketplaces(params url.Values, req *amazonRequest) error {
+	marketplaces := req.marketplaces
+	if len(marketplaces) == 0 {
+		marketplaces = []string{c.Region.MarketPlaceID}
+	} else if err := c.validateMarketplaces(marketplaces); err != nil {
+		return err
 	}
 
-	return respBytes, err
+	for i, id := range marketplaces {
+		params.Set(fmt.Sprintf("%s.%d", req.marketplaceParam(), (i+1)), id)
+	}
+	return nil
 }
 
-func (c *AmazonClient) parseAPIerrors(resp []byte) error {
-	errResponse := &ErrorResponse{}
-	err := xml.Unmarshal(resp, errResponse)
-	if err != nil {
-		return fmt.Errorf("UNABLE TO UNMARSHAL API RESPONSE: %s", err.Error())
+// validateMarketplaces checks that all marketplaces are in the endpoint group of the client's region
+func (c *AmazonClient) validateMarketplaces(marketplaces []string) error {
+	group := c.Region.EndpointGroup
+	if home, err := RegionByMarketplaceID(c.Region.MarketPlaceID); err == nil {
+		// Compare against the registry, so a client pointed at a proxy or test server still validates
+		group = home.EndpointGroup
 	}
 
-	return errResponse
+	for _, id := range marketplaces {
+		region, err := RegionByMarketplaceID(id)
+		if err != nil {
+			return err
+		}
+		if region.EndpointGroup != group {
+			return fmt.Errorf("marketplace %s (%s) cannot be requested with the %s marketplace %s",
+				id, region.Country, c.Region.Country, c.Region.MarketPlaceID)
+		}
+	}
+	return nil
 }
 
-func (c *AmazonClient) stringToSign(req *amazonRequest) (stringToSign string, err error) {
-	endpoint, err := url.Parse(req.endpoint)
-	if err != nil {
-		return
+// contextError prefers the context's cancellation error over the transport error it caused
+func contextError(ctx context.Context, err error) error {
+	if err == nil {
+		return nil
 	}
-	stringToSign = strings.Join([]string{
-		req.method,
-		strings.ToLower(endpoint.Host),
-		endpoint.Path,
-		CanonicalizedQueryString(req.params),
-	}, "\n")
-
-	return
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return ctxErr
+	}
+	return err
 }
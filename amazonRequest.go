@@ -5,10 +5,51 @@ import (
 	"net/url"
 )
 
+// amzMWS API sections, each served under its own endpoint path (e.g. Orders/2013-09-01)
+const (
+	ordersSection  = "Orders"
+	reportsSection = "Reports"
+	feedsSection   = "Feeds"
+)
+
 type amazonRequest struct {
-	endpoint string
-	params   url.Values
-	method   string
-	body     *bytes.Buffer
-	client   *AmazonClient
+	endpoint     string
+	section      string
+	params       url.Values
+	method       string
+	body         *bytes.Buffer
+	client       *AmazonClient
+	marketplaces []string
+}
+
+// operation returns the name of the amzMWS operation, i.e. the Action param
+func (r *amazonRequest) operation() string {
+	return r.params.Get("Action")
+}
+
+// clone returns a deep copy of the request params and body
+func (r *amazonRequest) clone() amazonRequest {
+	cp := *r
+	cp.params = copyValues(r.params)
+	cp.marketplaces = append([]string(nil), r.marketplaces...)
+	if r.body != nil {
+		cp.body = bytes.NewBuffer(append([]byte(nil), r.body.Bytes()...))
+	}
+	return cp
+}
+
+func copyValues(values url.Values) url.Values {
+	cp := make(url.Values, len(values))
+	for k, v := range values {
+		cp[k] = append([]string(nil), v...)
+	}
+	return cp
+}
+
+// marketplaceParam returns the marketplace list param prefix of the request's API section
+func (r *amazonRequest) marketplaceParam() string {
+	if r.section == ordersSection {
+		return "MarketplaceId.Id"
+	}
+	return "MarketplaceIdList.Id"
 }
@@ -0,0 +1,318 @@
+// Package cassette records amzMWS exchanges to disk and replays them, so integration tests run offline.
+//
+// A Cassette is an http.RoundTripper for the client's transport:
+//
+//	cas, err := cassette.New("testdata/list_orders.json", cassette.Replay)
+//	client := amazonmwsapi.NewAmazonClient(creds, "US", nil,
+//		amazonmwsapi.WithTransport(cas),
+//		amazonmwsapi.WithRateLimiter(nil),
+//		amazonmwsapi.WithRetryPolicy(amazonmwsapi.NoRetry))
+//
+// In Record mode requests are sent through Transport and the exchanges are saved by Save with
+// credentials, signatures and buyer PII scrubbed. In Replay mode no request leaves the process;
+// each request is matched to a recorded one by method, path, Action and all non-volatile params.
+package cassette
+
+import (
+	"bytes"
+	"crypto/md5"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+
+	amazonmwsapi "github.com/mike-holberger/amazonmws-go"
+)
+
+// Mode selects whether a Cassette records or replays
+type Mode int
+
+const (
+	// Replay serves recorded responses and never touches the network
+	Replay Mode = iota
+	// Record sends requests through Transport and records the exchanges
+	Record
+)
+
+// ErrNoInteraction is returned in Replay mode when no recorded interaction matches a request
+var ErrNoInteraction = errors.New("cassette: no recorded interaction matches request")
+
+// volatileParams change on every request or identify the seller; they are neither recorded nor matched
+var volatileParams = []string{
+	"Timestamp", "Signature", "SignatureMethod", "SignatureVersion",
+	"AWSAccessKeyId", "SellerId", "MWSAuthToken", "ContentMD5Value",
+}
+
+// volatileHeaders are response headers dropped when recording. Replayed server times would
+// otherwise be taken as clock skew by the client, and scrubbing changes the body length
+var volatileHeaders = []string{"Date", "X-Mws-Timestamp", "Set-Cookie", "Content-Length"}
+
+// piiColumns are the columns of flat file (TSV) reports masked when recording
+var piiColumns = map[string]bool{
+	"buyer-email":        true,
+	"buyer-name":         true,
+	"buyer-phone-number": true,
+	"recipient-name":     true,
+	"ship-address-1":     true,
+	"ship-address-2":     true,
+	"ship-address-3":     true,
+	"ship-city":          true,
+	"ship-state":         true,
+	"ship-postal-code":   true,
+	"ship-phone-number":  true,
+	"bill-address-1":     true,
+	"bill-address-2":     true,
+	"bill-address-3":     true,
+	"bill-city":          true,
+	"bill-state":         true,
+	"bill-postal-code":   true,
+	"merchant-id":        true,
+}
+
+const redacted = "REDACTED"
+
+// Request is the scrubbed request of an Interaction
+type Request struct {
+	Method string     `json:"method"`
+	Path   string     `json:"path"`
+	Action string     `json:"action"`
+	Params url.Values `json:"params"`
+	Body   string     `json:"body,omitempty"`
+}
+
+// Response is the scrubbed response of an Interaction
+type Response struct {
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header"`
+	Body       string      `json:"body"`
+}
+
+// Interaction is one recorded request and response
+type Interaction struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+}
+
+// Cassette is an http.RoundTripper that records or replays amzMWS exchanges
+type Cassette struct {
+	// Transport sends requests in Record mode, http.DefaultTransport when nil
+	Transport http.RoundTripper
+
+	path string
+	mode Mode
+
+	mu           sync.Mutex
+	interactions []Interaction
+	used         []bool
+}
+
+// New returns a Cassette backed by the file at path. In Replay mode the file must exist
+func New(path string, mode Mode) (*Cassette, error) {
+	c := &Cassette{path: path, mode: mode}
+	if mode == Record {
+		return c, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &c.interactions); err != nil {
+		return nil, fmt.Errorf("cassette %s: %w", path, err)
+	}
+	c.used = make([]bool, len(c.interactions))
+	return c, nil
+}
+
+// Interactions returns the interactions recorded or loaded so far
+func (c *Cassette) Interactions() []Interaction {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]Interaction(nil), c.interactions...)
+}
+
+// Save writes the recorded interactions to the cassette file
+func (c *Cassette) Save() error {
+	// Keep XML bodies readable in the file
+	var data bytes.Buffer
+	encoder := json.NewEncoder(&data)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	c.mu.Lock()
+	err := encoder.Encode(c.interactions)
+	c.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(c.path, data.Bytes(), 0644)
+}
+
+// RoundTrip implements http.RoundTripper
+func (c *Cassette) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := readRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+	recorded := newRequest(req, body)
+
+	if c.mode == Replay {
+		interaction, ok := c.match(recorded)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s %s %s", ErrNoInteraction, recorded.Method, recorded.Path, recorded.Action)
+		}
+		return interaction.Response.httpResponse(req), nil
+	}
+
+	transport := c.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	respBody, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+	c.mu.Lock()
+	c.interactions = append(c.interactions, Interaction{
+		Request:  recorded,
+		Response: newResponse(resp, respBody),
+	})
+	c.used = append(c.used, true)
+	c.mu.Unlock()
+	return resp, nil
+}
+
+// match returns the first unused interaction matching r. Once all matches are used the last one
+// is served again, so polling loops such as report status checks terminate
+func (c *Cassette) match(r Request) (Interaction, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	last := -1
+	for i, interaction := range c.interactions {
+		if !interaction.Request.matches(r) {
+			continue
+		}
+		if !c.used[i] {
+			c.used[i] = true
+			return interaction, true
+		}
+		last = i
+	}
+	if last < 0 {
+		return Interaction{}, false
+	}
+	return c.interactions[last], true
+}
+
+func (r Request) matches(o Request) bool {
+	return r.Method == o.Method && r.Path == o.Path && r.Action == o.Action &&
+		amazonmwsapi.CanonicalizedQueryString(r.Params) == amazonmwsapi.CanonicalizedQueryString(o.Params)
+}
+
+// newRequest returns the scrubbed form of req
+func newRequest(req *http.Request, body []byte) Request {
+	params := req.URL.Query()
+	for _, key := range volatileParams {
+		params.Del(key)
+	}
+	return Request{
+		Method: req.Method,
+		Path:   req.URL.Path,
+		Action: params.Get("Action"),
+		Params: params,
+		Body:   string(scrubBody(body)),
+	}
+}
+
+// newResponse returns the scrubbed form of resp
+func newResponse(resp *http.Response, body []byte) Response {
+	header := resp.Header.Clone()
+	for _, key := range volatileHeaders {
+		header.Del(key)
+	}
+	body = scrubBody(body)
+	if header.Get("Content-MD5") != "" {
+		sum := md5.Sum(body)
+		header.Set("Content-MD5", base64.StdEncoding.EncodeToString(sum[:]))
+	}
+	return Response{StatusCode: resp.StatusCode, Header: header, Body: string(body)}
+}
+
+func (r Response) httpResponse(req *http.Request) *http.Response {
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", r.StatusCode, http.StatusText(r.StatusCode)),
+		StatusCode:    r.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        r.Header.Clone(),
+		Body:          io.NopCloser(strings.NewReader(r.Body)),
+		ContentLength: int64(len(r.Body)),
+		Request:       req,
+	}
+}
+
+// readRequestBody reads the body of req and leaves req ready to be sent
+func readRequestBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+	body, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	return body, nil
+}
+
+// scrubBody masks credentials and buyer PII in XML bodies and flat file reports
+func scrubBody(body []byte) []byte {
+	if len(body) == 0 {
+		return body
+	}
+	if bytes.HasPrefix(bytes.TrimSpace(body), []byte("<")) {
+		return amazonmwsapi.RedactXML(body, amazonmwsapi.RedactAll)
+	}
+	return scrubFlatFile(body)
+}
+
+// scrubFlatFile masks the piiColumns of a tab separated report
+func scrubFlatFile(body []byte) []byte {
+	lines := strings.SplitAfter(string(body), "\n")
+	masked := map[int]bool{}
+	for i, name := range strings.Split(strings.TrimRight(lines[0], "\r\n"), "\t") {
+		if piiColumns[strings.ToLower(strings.TrimSpace(name))] {
+			masked[i] = true
+		}
+	}
+	if len(masked) == 0 {
+		return body
+	}
+
+	for n := 1; n < len(lines); n++ {
+		line := strings.TrimRight(lines[n], "\r\n")
+		ending := lines[n][len(line):]
+		fields := strings.Split(line, "\t")
+		for i := range fields {
+			if masked[i] && fields[i] != "" {
+				fields[i] = redacted
+			}
+		}
+		lines[n] = strings.Join(fields, "\t") + ending
+	}
+	return []byte(strings.Join(lines, ""))
+}
@@ -0,0 +1,216 @@
+package cassette
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	amazonmwsapi "github.com/mike-holberger/amazonmws-go"
+)
+
+// replayCreds differ from the credentials the cassettes were recorded with
+var replayCreds = amazonmwsapi.Creds{AccessID: "AKIAREPLAY", AccessKey: "replay-secret", Merchant: "A1REPLAYSELLER"}
+
+func replayClient(t *testing.T, name string) *amazonmwsapi.AmazonClient {
+	t.Helper()
+	cas, err := New(filepath.Join("testdata", name), Replay)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return amazonmwsapi.NewAmazonClient(replayCreds, "US", nil,
+		amazonmwsapi.WithTransport(cas),
+		amazonmwsapi.WithRateLimiter(nil),
+		amazonmwsapi.WithRetryPolicy(amazonmwsapi.NoRetry))
+}
+
+func TestReplayListOrders(t *testing.T) {
+	api := amazonmwsapi.NewOrdersAPI(replayClient(t, "list_orders.json"))
+	resp, err := api.ListOrders().
+		CreatedAfter(time.Date(2017, 2, 20, 0, 0, 0, 0, time.UTC)).
+		OrderStatus([]string{"Unshipped"}).
+		Do(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	orders := resp.ListOrdersResult.Orders.Order
+	if len(orders) != 2 || orders[0].AmazonOrderID != "902-3159896-1390916" || orders[1].AmazonOrderID != "483-3488972-0896720" {
+		t.Fatalf("got orders %+v", orders)
+	}
+	if orders[0].BuyerEmail != redacted || orders[0].ShippingAddress.CountryCode != "US" {
+		t.Errorf("got buyer %q in %q, want buyer redacted in US", orders[0].BuyerEmail, orders[0].ShippingAddress.CountryCode)
+	}
+	if resp.ResponseMetadata.RequestID != "88faca76-b600-46d2-b53c-0c8c4533e43a" || resp.ResponseMetadata.QuotaMax != 6 {
+		t.Errorf("got metadata %+v", resp.ResponseMetadata)
+	}
+}
+
+func TestReplayGetReport(t *testing.T) {
+	api := amazonmwsapi.NewReportsAPI(replayClient(t, "get_report.json"))
+	rows, err := api.GetReport("6024133148").Do(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 2 || rows[0]["sku"] != "SKU-RED-M" || rows[1]["quantity-purchased"] != "2" || rows[0]["buyer-email"] != redacted {
+		t.Fatalf("got rows %v", rows)
+	}
+
+	// The recorded Content-MD5 is recomputed for the scrubbed body
+	path := filepath.Join(t.TempDir(), "report.tsv")
+	if err := api.GetReport("6024133148").Download(context.Background(), path); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestReplaySubmitFeed(t *testing.T) {
+	api := amazonmwsapi.NewFeedsAPI(replayClient(t, "submit_feed.json"))
+	resp, err := api.SubmitFeed().
+		OrderAcknowledgements([]string{"902-3159896-1390916", "483-3488972-0896720"}).
+		Do(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	info := resp.SubmitFeedResult.FeedSubmissionInfo
+	if info.FeedSubmissionID != "2291326430" || info.FeedProcessingStatus != "_SUBMITTED_" {
+		t.Fatalf("got %+v", info)
+	}
+}
+
+func TestReplayUnmatched(t *testing.T) {
+	api := amazonmwsapi.NewOrdersAPI(replayClient(t, "list_orders.json"))
+	_, err := api.ListOrders().CreatedAfter(time.Date(2017, 2, 21, 0, 0, 0, 0, time.UTC)).Do(context.Background())
+	if !errors.Is(err, ErrNoInteraction) {
+		t.Fatalf("got %v, want ErrNoInteraction", err)
+	}
+}
+
+func TestReplayRepeatsLastMatch(t *testing.T) {
+	api := amazonmwsapi.NewFeedsAPI(replayClient(t, "submit_feed.json"))
+	for i := 0; i < 2; i++ {
+		_, err := api.SubmitFeed().OrderAcknowledgements([]string{"902-3159896-1390916"}).Do(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+var piiXML = regexp.MustCompile(`<(BuyerEmail|BuyerName|Name|AddressLine[123]|City|StateOrRegion|PostalCode|Phone|MerchantIdentifier)>([^<]*)<`)
+
+func TestCassettesScrubbed(t *testing.T) {
+	files, err := filepath.Glob(filepath.Join("testdata", "*.json"))
+	if err != nil || len(files) == 0 {
+		t.Fatal(files, err)
+	}
+	for _, file := range files {
+		cas, err := New(file, Replay)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, interaction := range cas.Interactions() {
+			for _, key := range volatileParams {
+				if _, ok := interaction.Request.Params[key]; ok {
+					t.Errorf("%s: %s param recorded", file, key)
+				}
+			}
+			for _, key := range volatileHeaders {
+				if interaction.Response.Header.Get(key) != "" {
+					t.Errorf("%s: %s header recorded", file, key)
+				}
+			}
+			for _, body := range []string{interaction.Request.Body, interaction.Response.Body} {
+				checkScrubbed(t, file, body)
+			}
+		}
+	}
+}
+
+func checkScrubbed(t *testing.T, file, body string) {
+	t.Helper()
+	if strings.HasPrefix(strings.TrimSpace(body), "<") {
+		for _, m := range piiXML.FindAllStringSubmatch(body, -1) {
+			if m[2] != redacted {
+				t.Errorf("%s: <%s> not scrubbed: %q", file, m[1], m[2])
+			}
+		}
+		return
+	}
+
+	lines := strings.Split(strings.TrimSpace(body), "\n")
+	header := strings.Split(lines[0], "\t")
+	for _, line := range lines[1:] {
+		for i, field := range strings.Split(line, "\t") {
+			if i < len(header) && piiColumns[header[i]] && field != "" && field != redacted {
+				t.Errorf("%s: column %s not scrubbed: %q", file, header[i], field)
+			}
+		}
+	}
+}
+
+func TestRecordScrubs(t *testing.T) {
+	const report = "order-id\tbuyer-email\tsku\n111-0000000-0000001\tbuyer@example.com\tSKU-1\n"
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sum := md5.Sum([]byte(report))
+		w.Header().Set("Content-MD5", base64.StdEncoding.EncodeToString(sum[:]))
+		w.Header().Set("X-Mws-Timestamp", "2001-01-01T00:00:00Z")
+		w.Write([]byte(report))
+	}))
+	defer srv.Close()
+
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	rec, err := New(path, Record)
+	if err != nil {
+		t.Fatal(err)
+	}
+	creds := amazonmwsapi.Creds{AccessID: "AKIARECORD", AccessKey: "record-secret", Merchant: "A1RECORDSELLER", AuthToken: "amzn.mws.record-token"}
+	client := amazonmwsapi.NewAmazonClient(creds, "US", nil,
+		amazonmwsapi.WithTransport(rec),
+		amazonmwsapi.WithRateLimiter(nil))
+	client.Region.Endpoint = srv.URL + "/"
+	rows, err := amazonmwsapi.NewReportsAPI(client).GetReport("1").Do(context.Background())
+	if err != nil || rows[0]["buyer-email"] != "buyer@example.com" {
+		t.Fatalf("got %v %v, want the unscrubbed report", rows, err)
+	}
+	if err := rec.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, secret := range []string{"AKIARECORD", "A1RECORDSELLER", "amzn.mws.record-token", "buyer@example.com", "2001-01-01"} {
+		if strings.Contains(string(data), secret) {
+			t.Errorf("cassette contains %q", secret)
+		}
+	}
+
+	// The replayed report passes the Content-MD5 check of Download
+	srv.Close()
+	cas, err := New(path, Replay)
+	if err != nil {
+		t.Fatal(err)
+	}
+	replay := amazonmwsapi.NewAmazonClient(replayCreds, "US", nil,
+		amazonmwsapi.WithTransport(cas),
+		amazonmwsapi.WithRateLimiter(nil))
+	replay.Region.Endpoint = srv.URL + "/"
+	if err := amazonmwsapi.NewReportsAPI(replay).GetReport("1").Download(context.Background(), filepath.Join(t.TempDir(), "r.tsv")); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestScrubFlatFile(t *testing.T) {
+	got := string(scrubFlatFile([]byte("order-id\tbuyer-email\r\n1\ta@b\r\n2\t\r\n")))
+	if want := "order-id\tbuyer-email\r\n1\tREDACTED\r\n2\t\r\n"; got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+}
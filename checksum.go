@@ -0,0 +1,47 @@
+package amazonmwsapi
+
+import (
+	"crypto/md5"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// ErrChecksumMismatch matches every *ChecksumMismatchError with errors.Is
+var ErrChecksumMismatch = errors.New("amzMWS checksum mismatch")
+
+// ChecksumMismatchError is returned by Download when the MD5 sum of the downloaded content
+// differs from the Content-MD5 header amzMWS sent with it
+type ChecksumMismatchError struct {
+	// Operation is the amzMWS Action, e.g. "GetReport"
+	Operation string
+	// Expected is the base64 encoded Content-MD5 header
+	Expected string
+	// Actual is the base64 encoded MD5 sum of the downloaded content
+	Actual string
+}
+
+func (e *ChecksumMismatchError) Error() string {
+	return fmt.Sprintf("amzMWS %s checksum mismatch: Content-MD5 %s, downloaded %s", e.Operation, e.Expected, e.Actual)
+}
+
+// Is makes errors.Is(err, ErrChecksumMismatch) true
+func (e *ChecksumMismatchError) Is(target error) bool {
+	return target == ErrChecksumMismatch
+}
+
+// verifyContentMD5 compares sum with the Content-MD5 header of header. Responses without the
+// header are not verified
+func verifyContentMD5(operation string, header http.Header, sum [md5.Size]byte) error {
+	expected := strings.TrimSpace(header.Get("Content-MD5"))
+	if expected == "" {
+		return nil
+	}
+	actual := base64.StdEncoding.EncodeToString(sum[:])
+	if actual != expected {
+		return &ChecksumMismatchError{Operation: operation, Expected: expected, Actual: actual}
+	}
+	return nil
+}
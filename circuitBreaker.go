@@ -0,0 +1,229 @@
+package amazonmwsapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// CircuitState is the state of the circuit breaker of an amzMWS API section
+type CircuitState int
+
+// Circuit breaker states
+const (
+	// CircuitClosed lets all requests through
+	CircuitClosed CircuitState = iota
+	// CircuitOpen fails all requests with ErrCircuitOpen
+	CircuitOpen
+	// CircuitHalfOpen lets a limited number of probe requests through
+	CircuitHalfOpen
+)
+
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	default:
+		return fmt.Sprintf("CircuitState(%d)", int(s))
+	}
+}
+
+// ErrCircuitOpen matches every *CircuitOpenError with errors.Is
+var ErrCircuitOpen = errors.New("amzMWS circuit open")
+
+// CircuitOpenError is returned without calling amzMWS while the circuit of the API section is open
+type CircuitOpenError struct {
+	// Section is the API section, "Orders", "Reports" or "Feeds"
+	Section string
+	// RetryAt is when the circuit half-opens and lets a probe request through
+	RetryAt time.Time
+	// Err is the error of the request's previous attempt when the circuit opened while it was retried
+	Err error
+}
+
+func (e *CircuitOpenError) Error() string {
+	msg := fmt.Sprintf("amzMWS circuit open for %s, retry at %s", e.Section, e.RetryAt.Format(time.RFC3339))
+	if e.Err != nil {
+		msg += ": " + e.Err.Error()
+	}
+	return msg
+}
+
+// Is makes errors.Is(err, ErrCircuitOpen) true
+func (e *CircuitOpenError) Is(target error) bool {
+	return target == ErrCircuitOpen
+}
+
+// Unwrap returns the error of the previous attempt, if any
+func (e *CircuitOpenError) Unwrap() error {
+	return e.Err
+}
+
+// CircuitBreakerConfig configures the circuit breakers of WithCircuitBreaker
+type CircuitBreakerConfig struct {
+	// FailureThreshold is the number of consecutive server errors that open a circuit, 5 by default
+	FailureThreshold int
+	// OpenTimeout is how long a circuit stays open before it half-opens, 30 seconds by default
+	OpenTimeout time.Duration
+	// HalfOpenProbes is the number of probe requests let through at once while half-open, and the
+	// number of successful probes that close the circuit, 1 by default
+	HalfOpenProbes int
+	// OnStateChange, if set, is called on every state change of the circuit of an API section
+	OnStateChange func(section string, from, to CircuitState)
+}
+
+// WithCircuitBreaker adds a circuit breaker to each API section (Orders, Reports and Feeds).
+// 5xx errors other than throttling and transport errors count as server errors
+func WithCircuitBreaker(config CircuitBreakerConfig) Option {
+	if config.FailureThreshold <= 0 {
+		config.FailureThreshold = 5
+	}
+	if config.OpenTimeout <= 0 {
+		config.OpenTimeout = 30 * time.Second
+	}
+	if config.HalfOpenProbes <= 0 {
+		config.HalfOpenProbes = 1
+	}
+	return func(c *AmazonClient) {
+		c.circuitBreakers = map[string]*circuitBreaker{}
+		for _, section := range []string{ordersSection, reportsSection, feedsSection} {
+			c.circuitBreakers[section] = &circuitBreaker{section: section, config: config, client: c, now: time.Now}
+		}
+	}
+}
+
+// CircuitState returns the state of the circuit breaker of section ("Orders", "Reports" or "Feeds"),
+// CircuitClosed when the client has no circuit breaker
+func (c *AmazonClient) CircuitState(section string) CircuitState {
+	b := c.circuitBreakers[section]
+	if b == nil {
+		return CircuitClosed
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.state
+}
+
+type circuitBreaker struct {
+	section string
+	config  CircuitBreakerConfig
+	client  *AmazonClient
+	now     func() time.Time
+
+	mu        sync.Mutex
+	state     CircuitState
+	failures  int
+	openedAt  time.Time
+	probes    int
+	successes int
+}
+
+// allow reports whether a request may be sent. done must be called with the request's outcome
+func (b *circuitBreaker) allow(ctx context.Context) (done func(err error), err error) {
+	if b == nil {
+		return func(error) {}, nil
+	}
+	b.mu.Lock()
+	from := b.state
+	if b.state == CircuitOpen {
+		retryAt := b.openedAt.Add(b.config.OpenTimeout)
+		if b.now().Before(retryAt) {
+			b.mu.Unlock()
+			return nil, &CircuitOpenError{Section: b.section, RetryAt: retryAt}
+		}
+		b.setState(CircuitHalfOpen)
+	}
+	probe := b.state == CircuitHalfOpen
+	if probe {
+		if b.probes >= b.config.HalfOpenProbes {
+			b.mu.Unlock()
+			b.changed(ctx, from, CircuitHalfOpen)
+			return nil, &CircuitOpenError{Section: b.section, RetryAt: b.now()}
+		}
+		b.probes++
+	}
+	to := b.state
+	b.mu.Unlock()
+	b.changed(ctx, from, to)
+
+	return func(err error) { b.record(ctx, probe, err) }, nil
+}
+
+// record counts the outcome of a request let through by allow
+func (b *circuitBreaker) record(ctx context.Context, probe bool, err error) {
+	outage := isOutage(err)
+	success := err == nil || (!outage && errors.As(err, new(*APIError)))
+
+	b.mu.Lock()
+	from := b.state
+	switch {
+	case b.state == CircuitClosed && outage:
+		b.failures++
+		if b.failures >= b.config.FailureThreshold {
+			b.setState(CircuitOpen)
+		}
+	case b.state == CircuitClosed && success:
+		b.failures = 0
+	case b.state == CircuitHalfOpen && probe:
+		b.probes--
+		if outage {
+			b.setState(CircuitOpen)
+		} else if success {
+			b.successes++
+			if b.successes >= b.config.HalfOpenProbes {
+				b.setState(CircuitClosed)
+			}
+		}
+	}
+	to := b.state
+	b.mu.Unlock()
+	b.changed(ctx, from, to)
+}
+
+// setState moves the circuit to state and resets its counters; b.mu must be held
+func (b *circuitBreaker) setState(state CircuitState) {
+	b.state = state
+	b.failures, b.probes, b.successes = 0, 0, 0
+	if state == CircuitOpen {
+		b.openedAt = b.now()
+	}
+}
+
+// changed logs and reports a state change, if any
+func (b *circuitBreaker) changed(ctx context.Context, from, to CircuitState) {
+	if to == from {
+		return
+	}
+
+	level := LevelWarn
+	if to == CircuitClosed {
+		level = LevelInfo
+	}
+	b.client.log(ctx, level, "CIRCUIT "+to.String()+" for Amazon "+b.section, Fields{
+		FieldSection: b.section,
+	})
+	if b.config.OnStateChange != nil {
+		b.config.OnStateChange(b.section, from, to)
+	}
+}
+
+// isOutage reports whether err suggests amzMWS is unavailable: a 5xx error other than
+// throttling, or a transient transport error. Certificate, proxy and other configuration
+// errors do not open the circuit
+func isOutage(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode >= http.StatusInternalServerError && !IsThrottled(apiErr)
+	}
+	return isTransient(err)
+}
@@ -0,0 +1,183 @@
+package amazonmwsapi
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// testBreaker returns the Orders circuit breaker of a client, driven by the returned fake clock
+func testBreaker(config CircuitBreakerConfig) (*circuitBreaker, *time.Time, *[]string) {
+	c := NewAmazonClient(Creds{}, "US", nil, WithRateLimiter(nil))
+	var changes []string
+	config.OnStateChange = func(section string, from, to CircuitState) {
+		changes = append(changes, from.String()+">"+to.String())
+	}
+	WithCircuitBreaker(config)(c)
+
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	b := c.circuitBreakers[ordersSection]
+	b.now = func() time.Time { return now }
+	return b, &now, &changes
+}
+
+var (
+	outageErr  = &APIError{StatusCode: 500, Code: "InternalError"}
+	senderErr  = &APIError{StatusCode: 400, Code: "InvalidParameterValue"}
+	throttleEr = &APIError{StatusCode: 503, Code: "RequestThrottled"}
+)
+
+// call runs one request through b, failing with err
+func call(t *testing.T, b *circuitBreaker, err error) error {
+	t.Helper()
+	done, allowErr := b.allow(context.Background())
+	if allowErr != nil {
+		return allowErr
+	}
+	done(err)
+	return nil
+}
+
+func TestCircuitOpensAfterConsecutiveOutages(t *testing.T) {
+	b, _, changes := testBreaker(CircuitBreakerConfig{FailureThreshold: 3})
+
+	call(t, b, outageErr)
+	call(t, b, outageErr)
+	call(t, b, senderErr) // any answer from amzMWS resets the count
+	call(t, b, outageErr)
+	call(t, b, throttleEr)
+	call(t, b, outageErr)
+	call(t, b, outageErr)
+	if b.state != CircuitClosed {
+		t.Fatalf("got %s after 2 consecutive outages, want closed", b.state)
+	}
+	call(t, b, outageErr)
+	if b.state != CircuitOpen {
+		t.Fatalf("got %s after 3 consecutive outages, want open", b.state)
+	}
+
+	err := call(t, b, nil)
+	var openErr *CircuitOpenError
+	if !errors.Is(err, ErrCircuitOpen) || !errors.As(err, &openErr) || openErr.Section != ordersSection {
+		t.Fatalf("got %v, want a CircuitOpenError for Orders", err)
+	}
+	if want := []string{"closed>open"}; !reflect.DeepEqual(*changes, want) {
+		t.Errorf("got changes %v, want %v", *changes, want)
+	}
+}
+
+func TestCircuitHalfOpenProbes(t *testing.T) {
+	b, now, changes := testBreaker(CircuitBreakerConfig{FailureThreshold: 1, OpenTimeout: time.Minute, HalfOpenProbes: 2})
+	call(t, b, outageErr)
+
+	*now = now.Add(59 * time.Second)
+	if err := call(t, b, nil); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("got %v before OpenTimeout, want ErrCircuitOpen", err)
+	}
+
+	// Two probes are let through at once, a third waits for their outcome
+	*now = now.Add(time.Second)
+	probe1, err1 := b.allow(context.Background())
+	probe2, err2 := b.allow(context.Background())
+	_, err3 := b.allow(context.Background())
+	if err1 != nil || err2 != nil || !errors.Is(err3, ErrCircuitOpen) {
+		t.Fatalf("got %v %v %v, want 2 probes allowed and the third rejected", err1, err2, err3)
+	}
+	probe1(nil)
+	if b.state != CircuitHalfOpen {
+		t.Fatalf("got %s after 1 successful probe, want half-open", b.state)
+	}
+	probe2(nil)
+	if b.state != CircuitClosed {
+		t.Fatalf("got %s after 2 successful probes, want closed", b.state)
+	}
+
+	want := []string{"closed>open", "open>half-open", "half-open>closed"}
+	if !reflect.DeepEqual(*changes, want) {
+		t.Errorf("got changes %v, want %v", *changes, want)
+	}
+}
+
+func TestCircuitFailedProbeReopens(t *testing.T) {
+	b, now, _ := testBreaker(CircuitBreakerConfig{FailureThreshold: 1, OpenTimeout: time.Minute})
+	call(t, b, outageErr)
+
+	*now = now.Add(time.Minute)
+	call(t, b, outageErr)
+	if b.state != CircuitOpen || !b.openedAt.Equal(*now) {
+		t.Fatalf("got %s opened at %s, want open at %s", b.state, b.openedAt, *now)
+	}
+
+	*now = now.Add(time.Minute)
+	if err := call(t, b, senderErr); err != nil || b.state != CircuitClosed {
+		t.Fatalf("got %v in %s, want the probe answered and the circuit closed", err, b.state)
+	}
+}
+
+func TestIsOutage(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"nil", nil, false},
+		{"internal error", outageErr, true},
+		{"throttled", throttleEr, false},
+		{"invalid parameter", senderErr, false},
+		{"timeout", transportError(timeoutError{}), true},
+		{"connection refused", transportError(os.NewSyscallError("connect", syscall.ECONNREFUSED)), true},
+		{"x509", transportError(x509.UnknownAuthorityError{}), false},
+		{"canceled", transportError(context.Canceled), false},
+	}
+	for _, tt := range tests {
+		if got := isOutage(tt.err); got != tt.want {
+			t.Errorf("%s: isOutage(%v) = %v, want %v", tt.name, tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestCircuitOpenedWhileRetryingKeepsCause(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(`<ErrorResponse><Error><Type>Receiver</Type><Code>InternalError</Code><Message>down</Message></Error></ErrorResponse>`))
+	}))
+	defer srv.Close()
+	c := NewAmazonClient(Creds{AccessID: "a", AccessKey: "k", Merchant: "m"}, "US", nil,
+		WithRateLimiter(nil),
+		WithRetryPolicy(ExponentialBackoff{MaxRetries: 3}),
+		WithCircuitBreaker(CircuitBreakerConfig{FailureThreshold: 2}))
+	c.Region.Endpoint = srv.URL + "/"
+
+	_, err := NewOrdersAPI(c).GetOrder([]string{"1"}).Do(context.Background())
+	var apiErr *APIError
+	if !errors.Is(err, ErrCircuitOpen) || !errors.As(err, &apiErr) || apiErr.Code != "InternalError" {
+		t.Fatalf("got %v, want ErrCircuitOpen wrapping the InternalError", err)
+	}
+}
+
+func TestTLSErrorDoesNotOpenCircuit(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+	c := NewAmazonClient(Creds{AccessID: "a", AccessKey: "k", Merchant: "m"}, "US", nil,
+		WithRateLimiter(nil),
+		WithRetryPolicy(NoRetry),
+		WithCircuitBreaker(CircuitBreakerConfig{FailureThreshold: 2}))
+	c.Region.Endpoint = srv.URL + "/"
+
+	for i := 0; i < 3; i++ {
+		_, err := NewOrdersAPI(c).GetOrder([]string{"1"}).Do(context.Background())
+		if err == nil || errors.Is(err, ErrCircuitOpen) {
+			t.Fatalf("call %d: got %v, want the certificate error", i+1, err)
+		}
+	}
+	if state := c.CircuitState(ordersSection); state != CircuitClosed {
+		t.Errorf("got %s, want closed", state)
+	}
+}
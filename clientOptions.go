@@ -0,0 +1,35 @@
+package amazonmwsapi
+
+import (
+	"net/http"
+	"time"
+)
+
+// Option configures optional AmazonClient settings
+type Option func(*AmazonClient)
+
+// WithHTTPClient sets the http.Client shared by every API section of the AmazonClient.
+// The client is copied, so later options (e.g. WithTimeout) do not modify the caller's value
+func WithHTTPClient(hc *http.Client) Option {
+	return func(c *AmazonClient) {
+		if hc == nil {
+			return
+		}
+		cp := *hc
+		c.httpClient = &cp
+	}
+}
+
+// WithTimeout sets the overall time limit for a single request to amzMWS, including report downloads
+func WithTimeout(d time.Duration) Option {
+	return func(c *AmazonClient) {
+		c.httpClient.Timeout = d
+	}
+}
+
+// WithTransport sets the http.RoundTripper used to send requests, e.g. for proxies, custom TLS or tests
+func WithTransport(rt http.RoundTripper) Option {
+	return func(c *AmazonClient) {
+		c.httpClient.Transport = rt
+	}
+}
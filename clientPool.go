@@ -0,0 +1,126 @@
+package amazonmwsapi
+
+import (
+	"sync"
+)
+
+// CredentialsSource looks up the credentials of a seller account
+type CredentialsSource interface {
+	SellerCredentials(sellerID string) (Creds, error)
+}
+
+// CredentialsSourceFunc adapts a function to the CredentialsSource interface
+type CredentialsSourceFunc func(sellerID string) (Creds, error)
+
+// SellerCredentials implements CredentialsSource
+func (f CredentialsSourceFunc) SellerCredentials(sellerID string) (Creds, error) {
+	return f(sellerID)
+}
+
+// ClientPool lazily builds and caches one AmazonClient per seller account of a region.
+// Each seller's client keeps its own rate limiter quota state, so a RateLimiter should not be passed
+// in the pool options unless all sellers are meant to share it. A ClientPool is safe for concurrent use
+type ClientPool struct {
+	source      CredentialsSource
+	countryCode string
+	log         Logger
+	opts        []Option
+
+	mu      sync.Mutex
+	clients map[string]*poolEntry
+}
+
+type poolEntry struct {
+	ready  chan struct{}
+	client *AmazonClient
+	err    error
+}
+
+// NewClientPool creates a ClientPool building clients for countryCode with the given Logger and options
+func NewClientPool(source CredentialsSource, countryCode string, log Logger, opts ...Option) *ClientPool {
+	return &ClientPool{
+		source:      source,
+		countryCode: countryCode,
+		log:         log,
+		opts:        opts,
+		clients:     map[string]*poolEntry{},
+	}
+}
+
+// Client returns the AmazonClient of a seller, building it on first use.
+// A failed credentials lookup is not cached, so the next call tries again
+func (p *ClientPool) Client(sellerID string) (*AmazonClient, error) {
+	p.mu.Lock()
+	entry, ok := p.clients[sellerID]
+	if !ok {
+		entry = &poolEntry{ready: make(chan struct{})}
+		p.clients[sellerID] = entry
+	}
+	p.mu.Unlock()
+
+	if !ok {
+		entry.client, entry.err = p.newClient(sellerID)
+		if entry.err != nil {
+			p.removeEntry(sellerID, entry)
+		}
+		close(entry.ready)
+	}
+
+	<-entry.ready
+	return entry.client, entry.err
+}
+
+func (p *ClientPool) newClient(sellerID string) (*AmazonClient, error) {
+	creds, err := p.source.SellerCredentials(sellerID)
+	if err != nil {
+		return nil, err
+	}
+	if creds.Merchant == "" {
+		creds.Merchant = sellerID
+	}
+	return NewAmazonClient(creds, p.countryCode, p.log, p.opts...), nil
+}
+
+// Remove drops the cached client of a seller, e.g. after its access was revoked
+func (p *ClientPool) Remove(sellerID string) {
+	p.mu.Lock()
+	delete(p.clients, sellerID)
+	p.mu.Unlock()
+}
+
+// removeEntry drops the cached entry of a seller if it is still entry, so a failed build does not
+// evict an entry added after a concurrent Remove
+func (p *ClientPool) removeEntry(sellerID string, entry *poolEntry) {
+	p.mu.Lock()
+	if p.clients[sellerID] == entry {
+		delete(p.clients, sellerID)
+	}
+	p.mu.Unlock()
+}
+
+// Orders returns the OrdersAPI of a seller
+func (p *ClientPool) Orders(sellerID string) (*OrdersAPI, error) {
+	cli, err := p.Client(sellerID)
+	if err != nil {
+		return nil, err
+	}
+	return NewOrdersAPI(cli), nil
+}
+
+// Reports returns the ReportsAPI of a seller
+func (p *ClientPool) Reports(sellerID string) (*ReportsAPI, error) {
+	cli, err := p.Client(sellerID)
+	if err != nil {
+		return nil, err
+	}
+	return NewReportsAPI(cli), nil
+}
+
+// Feeds returns the FeedsAPI of a seller
+func (p *ClientPool) Feeds(sellerID string) (*FeedsAPI, error) {
+	cli, err := p.Client(sellerID)
+	if err != nil {
+		return nil, err
+	}
+	return NewFeedsAPI(cli), nil
+}
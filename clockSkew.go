@@ -0,0 +1,47 @@
+package amazonmwsapi
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// skewWarnThreshold is the measured skew above which a warning is logged; MWS rejects
+// requests whose Timestamp is more than 15 minutes off with RequestExpired
+const skewWarnThreshold = 5 * time.Minute
+
+// ClockSkew returns the last measured offset of the amzMWS server clock from the local clock.
+// It is added to the Timestamp of every signed request
+func (c *AmazonClient) ClockSkew() time.Duration {
+	return time.Duration(c.skew.Load())
+}
+
+// now returns the current time corrected by the measured clock skew
+func (c *AmazonClient) now() time.Time {
+	return time.Now().Add(c.ClockSkew())
+}
+
+// observeServerTime measures the clock skew from the x-mws-timestamp, or else the Date, header of a
+// response, assuming the server stamped it halfway between sending the request and receiving the response
+func (c *AmazonClient) observeServerTime(ctx context.Context, header http.Header, sent, received time.Time) {
+	serverTime, err := time.Parse(time.RFC3339, header.Get("x-mws-timestamp"))
+	if err != nil {
+		if serverTime, err = http.ParseTime(header.Get("Date")); err != nil {
+			return
+		}
+	}
+
+	skew := serverTime.Sub(sent.Add(received.Sub(sent) / 2))
+	previous := time.Duration(c.skew.Swap(int64(skew)))
+	if (skew > skewWarnThreshold || skew < -skewWarnThreshold) &&
+		(previous <= skewWarnThreshold && previous >= -skewWarnThreshold) {
+		c.log(ctx, LevelWarn, "CLOCK SKEW detected, correcting request timestamps", Fields{
+			FieldClockSkew: skew,
+		})
+	}
+}
+
+// IsRequestExpired reports whether err is an amzMWS RequestExpired error, usually caused by clock skew
+func IsRequestExpired(err error) bool {
+	return hasErrorCode(err, "RequestExpired")
+}
@@ -0,0 +1,97 @@
+package amazonmwsapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// CredentialsProvider supplies the credentials used to sign each request,
+// allowing secret keys to be rotated without rebuilding the AmazonClient
+type CredentialsProvider interface {
+	Credentials(ctx context.Context) (Creds, error)
+}
+
+// WithCredentialsProvider sets the CredentialsProvider consulted on every request,
+// replacing the Creds passed to NewAmazonClient
+func WithCredentialsProvider(p CredentialsProvider) Option {
+	return func(c *AmazonClient) {
+		c.credentials = p
+	}
+}
+
+// StaticCredentials returns a CredentialsProvider that always supplies creds
+func StaticCredentials(creds Creds) CredentialsProvider {
+	return staticCredentials(creds)
+}
+
+type staticCredentials Creds
+
+func (s staticCredentials) Credentials(ctx context.Context) (Creds, error) {
+	return Creds(s), nil
+}
+
+// EnvCredentials returns a CredentialsProvider reading the environment variables
+// <prefix>ACCESS_ID, <prefix>ACCESS_KEY, <prefix>MERCHANT_ID, <prefix>MWS_AUTH_TOKEN and
+// <prefix>COMPANY_NAME on every request
+func EnvCredentials(prefix string) CredentialsProvider {
+	return envCredentials(prefix)
+}
+
+type envCredentials string
+
+func (prefix envCredentials) Credentials(ctx context.Context) (Creds, error) {
+	p := string(prefix)
+	return Creds{
+		AccessID:    os.Getenv(p + "ACCESS_ID"),
+		AccessKey:   os.Getenv(p + "ACCESS_KEY"),
+		CompanyName: os.Getenv(p + "COMPANY_NAME"),
+		Merchant:    os.Getenv(p + "MERCHANT_ID"),
+		AuthToken:   os.Getenv(p + "MWS_AUTH_TOKEN"),
+	}, nil
+}
+
+// FileCredentials returns a CredentialsProvider reading Creds from a JSON file, e.g.
+// {"access_id": "...", "access_key": "...", "merchant_id": "..."}.
+// The file is re-read whenever its modification time or size changes
+func FileCredentials(path string) CredentialsProvider {
+	return &fileCredentials{path: path}
+}
+
+type fileCredentials struct {
+	path string
+
+	mu      sync.Mutex
+	loaded  bool
+	modTime time.Time
+	size    int64
+	creds   Creds
+}
+
+func (f *fileCredentials) Credentials(ctx context.Context) (Creds, error) {
+	info, err := os.Stat(f.path)
+	if err != nil {
+		return Creds{}, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.loaded && info.ModTime().Equal(f.modTime) && info.Size() == f.size {
+		return f.creds, nil
+	}
+
+	data, err := os.ReadFile(f.path)
+	if err != nil {
+		return Creds{}, err
+	}
+	creds := Creds{}
+	if err := json.Unmarshal(data, &creds); err != nil {
+		return Creds{}, fmt.Errorf("invalid credentials file %s: %s", f.path, err.Error())
+	}
+
+	f.loaded, f.modTime, f.size, f.creds = true, info.ModTime(), info.Size(), creds
+	return creds, nil
+}
@@ -0,0 +1,77 @@
+package amazonmwsapi
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestFileCredentials(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "creds.json")
+	if err := os.WriteFile(path, []byte(`{"access_id": "id", "access_key": "key1", "merchant_id": "seller"}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	provider := FileCredentials(path)
+	if creds, err := provider.Credentials(context.Background()); err != nil || creds.AccessKey != "key1" || creds.Merchant != "seller" {
+		t.Fatalf("got %+v %v, want key1 for seller", creds, err)
+	}
+
+	// A rotated key is read once the file changed
+	if err := os.WriteFile(path, []byte(`{"access_id": "id", "access_key": "key2", "merchant_id": "seller"}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	later := time.Now().Add(time.Second)
+	os.Chtimes(path, later, later)
+	if creds, err := provider.Credentials(context.Background()); err != nil || creds.AccessKey != "key2" {
+		t.Fatalf("got %+v %v, want the rotated key2", creds, err)
+	}
+
+	os.WriteFile(path, []byte("access_id: id\n"), 0600)
+	os.Chtimes(path, later.Add(time.Second), later.Add(time.Second))
+	if _, err := provider.Credentials(context.Background()); err == nil {
+		t.Fatal("non-JSON credentials file accepted")
+	}
+}
+
+// credentialsFunc adapts a function to the CredentialsProvider interface
+type credentialsFunc func(ctx context.Context) (Creds, error)
+
+func (f credentialsFunc) Credentials(ctx context.Context) (Creds, error) {
+	return f(ctx)
+}
+
+func TestRateLimiterFollowsSeller(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`<GetOrderResponse></GetOrderResponse>`))
+	}))
+	defer srv.Close()
+
+	var creds Creds
+	var credsErr error
+	c := NewAmazonClient(Creds{}, "US", nil, WithCredentialsProvider(credentialsFunc(func(ctx context.Context) (Creds, error) {
+		return creds, credsErr
+	})))
+	c.Region.Endpoint = srv.URL + "/"
+
+	// A provider failing at construction neither fails nor pins the client to a limiter
+	credsErr = errors.New("credentials unavailable")
+	if _, err := NewOrdersAPI(c).GetOrder([]string{"1"}).Do(context.Background()); !errors.Is(err, credsErr) {
+		t.Fatalf("got %v, want the provider's error", err)
+	}
+
+	credsErr = nil
+	for _, seller := range []string{"limiter-seller-1", "limiter-seller-2"} {
+		creds = Creds{AccessID: "id", AccessKey: "key", Merchant: seller}
+		if _, err := NewOrdersAPI(c).GetOrder([]string{"1"}).Do(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+		if got := sellerRateLimiter(seller, c.Region).buckets["GetOrder"]; got == nil || got.tokens >= float64(DefaultQuotas["GetOrder"].MaxRequests) {
+			t.Errorf("%s: GetOrder not counted against the seller's limiter", seller)
+		}
+	}
+}
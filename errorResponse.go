@@ -3,6 +3,10 @@ package amazonmwsapi
 import (
 	"bytes"
 	"encoding/xml"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
 )
 
 // ErrorResponse holds error data from errored API call
@@ -27,3 +31,89 @@ func (e *ErrorResponse) Error() string {
 
 	return buf.String()
 }
+
+// apiError converts the (first) error of the response into an *APIError
+func (e *ErrorResponse) apiError(operation string, statusCode int) *APIError {
+	apiErr := &APIError{
+		StatusCode: statusCode,
+		Operation:  operation,
+		RequestID:  e.RequestID,
+	}
+	if len(e.Errors) > 0 {
+		apiErr.Type = e.Errors[0].Type
+		apiErr.Code = e.Errors[0].Code
+		apiErr.Message = e.Errors[0].Message
+	}
+	return apiErr
+}
+
+// APIError is returned for every amzMWS call that fails with an error response.
+// Use errors.As to inspect it, or the IsThrottled, IsInvalidParameter and IsAccessDenied helpers
+type APIError struct {
+	StatusCode int
+	Operation  string
+	Type       string
+	Code       string
+	Message    string
+	RequestID  string
+}
+
+// newAPIError parses a non-200 response body; bodies that are not an ErrorResponse are kept as Message
+func newAPIError(operation string, statusCode int, body []byte) *APIError {
+	errResponse := &ErrorResponse{}
+	if err := xml.Unmarshal(body, errResponse); err == nil {
+		return errResponse.apiError(operation, statusCode)
+	}
+	return &APIError{
+		StatusCode: statusCode,
+		Operation:  operation,
+		Message:    strings.TrimSpace(string(body)),
+	}
+}
+
+func (e *APIError) Error() string {
+	msg := fmt.Sprintf("amzMWS %s failed with HTTP %d", e.Operation, e.StatusCode)
+	if e.Code != "" {
+		msg += ": " + e.Code
+	}
+	if e.Message != "" {
+		msg += ": " + e.Message
+	}
+	if e.RequestID != "" {
+		msg += " (RequestId " + e.RequestID + ")"
+	}
+	return msg
+}
+
+// IsThrottled reports whether err is an amzMWS RequestThrottled or QuotaExceeded error
+func IsThrottled(err error) bool {
+	return hasErrorCode(err, "RequestThrottled", "QuotaExceeded")
+}
+
+// IsInvalidParameter reports whether err is an amzMWS error caused by a missing or invalid request parameter
+func IsInvalidParameter(err error) bool {
+	return hasErrorCode(err, "InvalidParameterValue", "InvalidParameter", "MissingParameter")
+}
+
+// IsAccessDenied reports whether err is an amzMWS authentication or authorization error
+func IsAccessDenied(err error) bool {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	return apiErr.StatusCode == http.StatusUnauthorized || apiErr.StatusCode == http.StatusForbidden ||
+		hasErrorCode(err, "AccessDenied", "InvalidAccessKeyId", "SignatureDoesNotMatch")
+}
+
+func hasErrorCode(err error, codes ...string) bool {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	for _, code := range codes {
+		if apiErr.Code == code {
+			return true
+		}
+	}
+	return false
+}
@@ -23,8 +23,23 @@ func (api *FeedsAPI) SubmitFeed() *SubmitFeedRequest {
 		amazonRequest: amazonRequest{
 			client:   api.client,
 			endpoint: api.endpoint,
+			section:  feedsSection,
 			params:   url.Values{"Action": {"SubmitFeed"}, "Version": {feedsAPIversion}},
 			method:   "POST",
 		},
 	}
 }
+
+// GetFeedSubmissionResult downloads the processing report of a submitted feed
+func (api *FeedsAPI) GetFeedSubmissionResult(feedSubmissionID string) *GetFeedSubmissionResultRequest {
+	return &GetFeedSubmissionResultRequest{
+		amazonRequest{
+			client:   api.client,
+			endpoint: api.endpoint,
+			section:  feedsSection,
+			params: url.Values{"Action": {"GetFeedSubmissionResult"}, "Version": {feedsAPIversion},
+				"FeedSubmissionId": {feedSubmissionID}},
+			method: "POST",
+		},
+	}
+}
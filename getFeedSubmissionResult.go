@@ -0,0 +1,19 @@
+package amazonmwsapi
+
+import "context"
+
+// GetFeedSubmissionResultRequest requests the processing report of a submitted feed for download
+type GetFeedSubmissionResultRequest struct {
+	amazonRequest
+}
+
+// Clone returns a copy of the request that can be modified and executed independently
+func (r *GetFeedSubmissionResultRequest) Clone() *GetFeedSubmissionResultRequest {
+	return &GetFeedSubmissionResultRequest{r.amazonRequest.clone()}
+}
+
+// Download sends request to amazonMWS feeds API and downloads the processing report to filePath. filePath
+// is only written once the report matches its Content-MD5 header, otherwise a *ChecksumMismatchError is returned
+func (r *GetFeedSubmissionResultRequest) Download(ctx context.Context, filePath string) error {
+	return r.client.downloadReport(ctx, &r.amazonRequest, filePath)
+}
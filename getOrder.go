@@ -10,18 +10,19 @@ type GetOrderRequest struct {
 	amazonRequest
 }
 
+// Clone returns a copy of the request that can be modified and executed independently
+func (r *GetOrderRequest) Clone() *GetOrderRequest {
+	return &GetOrderRequest{r.amazonRequest.clone()}
+}
+
 // Do sends request to Amazon API
 func (r *GetOrderRequest) Do(ctx context.Context) (*GetOrderResponse, error) {
-	respBytes, err := r.client.callAPI(ctx, &r.amazonRequest)
-	if err != nil {
-		return nil, err
-	}
-
 	xmlResponse := &GetOrderResponse{}
-	err = xml.Unmarshal(respBytes, xmlResponse)
+	header, err := r.client.callAPI(ctx, &r.amazonRequest, xmlResponse)
 	if err != nil {
-		return nil, r.client.parseAPIerrors(respBytes)
+		return nil, err
 	}
+	xmlResponse.ResponseMetadata.setHeader(header)
 
 	return xmlResponse, nil
 }
@@ -55,7 +56,5 @@ type GetOrderResponse struct {
 			} `xml:"Order"`
 		} `xml:"Orders"`
 	} `xml:"GetOrderResult"`
-	ResponseMetadata struct {
-		RequestID string `xml:"RequestId"`
-	} `xml:"ResponseMetadata"`
+	ResponseMetadata ResponseMetadata `xml:"ResponseMetadata"`
 }
@@ -1,9 +1,9 @@
 package amazonmwsapi
 
 import (
-	"bytes"
 	"context"
 	"encoding/csv"
+	"io"
 )
 
 // GetReportRequest requests a single amzMWS report for download
@@ -11,39 +11,64 @@ type GetReportRequest struct {
 	amazonRequest
 }
 
+// Clone returns a copy of the request that can be modified and executed independently
+func (r *GetReportRequest) Clone() *GetReportRequest {
+	return &GetReportRequest{r.amazonRequest.clone()}
+}
+
 // Do sends request to amazonMWS reports API and returns report data maps
 func (r *GetReportRequest) Do(ctx context.Context) ([]map[string]string, error) {
-	respBytes, err := r.client.callAPI(ctx, &r.amazonRequest)
+	body, err := r.Stream(ctx)
 	if err != nil {
 		return nil, err
 	}
+	defer body.Close()
+
+	return r.parseTSVData(body)
+}
 
-	return r.parseTSVData(respBytes)
+// Stream sends request to amazonMWS reports API and returns the report body unread, so large
+// reports can be processed row by row. The caller must close it. Reading the end of a report that
+// does not match its Content-MD5 header returns a *ChecksumMismatchError
+func (r *GetReportRequest) Stream(ctx context.Context) (io.ReadCloser, error) {
+	return r.client.streamReport(ctx, &r.amazonRequest)
 }
 
-func (r *GetReportRequest) parseTSVData(rep []byte) ([]map[string]string, error) {
-	// Parse TSV into string data slices
-	tsvReader := csv.NewReader(bytes.NewReader(rep))
+func (r *GetReportRequest) parseTSVData(rep io.Reader) ([]map[string]string, error) {
+	// Parse TSV rows as they are read
+	tsvReader := csv.NewReader(rep)
 	tsvReader.Comma = '\t'
 	tsvReader.FieldsPerRecord = -1
-	data, err := tsvReader.ReadAll()
+	header, err := tsvReader.Read()
+	if err == io.EOF {
+		return []map[string]string{}, nil
+	}
 	if err != nil {
 		return nil, err
 	}
 
 	// Parse into maps indexed by column header title
-	rowMaps := make([]map[string]string, (len(data) - 1))
-	for rowIndex, dataRow := range data[1:] {
-		rowMap := make(map[string]string)
-		for colIndex, header := range data[0] {
-			rowMap[header] = dataRow[colIndex]
+	rowMaps := []map[string]string{}
+	for {
+		dataRow, err := tsvReader.Read()
+		if err == io.EOF {
+			return rowMaps, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		rowMap := make(map[string]string, len(header))
+		for colIndex, title := range header {
+			if colIndex < len(dataRow) {
+				rowMap[title] = dataRow[colIndex]
+			}
 		}
-		rowMaps[rowIndex] = rowMap
+		rowMaps = append(rowMaps, rowMap)
 	}
-	return rowMaps, nil
 }
 
-// Download sends request to amazonMWS reports API and downloads report to filepath
+// Download sends request to amazonMWS reports API and downloads report to filePath. filePath is only
+// written once the report matches its Content-MD5 header, otherwise a *ChecksumMismatchError is returned
 func (r *GetReportRequest) Download(ctx context.Context, filePath string) error {
 	err := r.client.downloadReport(ctx, &r.amazonRequest, filePath)
 	if err != nil {
@@ -13,6 +13,11 @@ type GetReportListRequest struct {
 	amazonRequest
 }
 
+// Clone returns a copy of the request that can be modified and executed independently
+func (r *GetReportListRequest) Clone() *GetReportListRequest {
+	return &GetReportListRequest{r.amazonRequest.clone()}
+}
+
 // ReportTypes add requested report types to request
 func (r *GetReportListRequest) ReportTypes(reportTypes []string) *GetReportListRequest {
 	for i, rep := range reportTypes {
@@ -24,22 +29,18 @@ func (r *GetReportListRequest) ReportTypes(reportTypes []string) *GetReportListR
 
 // Acknowledged adds ack param to request
 func (r *GetReportListRequest) Acknowledged(ack bool) *GetReportListRequest {
-	r.params.Add("Acknowledged", strconv.FormatBool(ack))
+	r.params.Set("Acknowledged", strconv.FormatBool(ack))
 	return r
 }
 
 // Do sends request to amazonMWS reports API
 func (r *GetReportListRequest) Do(ctx context.Context) (*GetReportListResponse, error) {
-	respBytes, err := r.client.callAPI(ctx, &r.amazonRequest)
-	if err != nil {
-		return nil, err
-	}
-
 	xmlResponse := &GetReportListResponse{}
-	err = xml.Unmarshal(respBytes, xmlResponse)
+	header, err := r.client.callAPI(ctx, &r.amazonRequest, xmlResponse)
 	if err != nil {
-		return nil, r.client.parseAPIerrors(respBytes)
+		return nil, err
 	}
+	xmlResponse.ResponseMetadata.setHeader(header)
 
 	return xmlResponse, nil
 }
@@ -49,6 +50,7 @@ func (r *GetReportListRequest) DoNext(ctx context.Context, nextToken string) (*G
 	nextReq := &amazonRequest{
 		client:   r.client,
 		endpoint: r.endpoint,
+		section:  r.section,
 		method:   r.method,
 		params: url.Values{
 			"Action":    {"GetReportListByNextToken"},
@@ -57,16 +59,12 @@ func (r *GetReportListRequest) DoNext(ctx context.Context, nextToken string) (*G
 		},
 	}
 
-	respBytes, err := r.client.callAPI(ctx, nextReq)
-	if err != nil {
-		return nil, err
-	}
-
 	xmlResponse := &GetReportListByNextTokenResponse{}
-	err = xml.Unmarshal(respBytes, xmlResponse)
+	header, err := r.client.callAPI(ctx, nextReq, xmlResponse)
 	if err != nil {
-		return nil, r.client.parseAPIerrors(respBytes)
+		return nil, err
 	}
+	xmlResponse.ResponseMetadata.setHeader(header)
 
 	return xmlResponse, nil
 }
@@ -83,6 +81,9 @@ func (r *GetReportListRequest) DoAll(ctx context.Context) ([]ReportInfo, error)
 	hasNext := firstResp.GetReportListResult.HasNext
 	nextToken := firstResp.GetReportListResult.NextToken
 	for hasNext {
+		if err := ctx.Err(); err != nil {
+			return reports, err
+		}
 		nextResp, err := r.DoNext(ctx, nextToken)
 		if err != nil {
 			return reports, err
@@ -114,9 +115,7 @@ type GetReportListResponse struct {
 		HasNext    bool         `xml:"HasNext"`
 		ReportInfo []ReportInfo `xml:"ReportInfo"`
 	} `xml:"GetReportListResult"`
-	ResponseMetadata struct {
-		RequestID string `xml:"RequestId"`
-	} `xml:"ResponseMetadata"`
+	ResponseMetadata ResponseMetadata `xml:"ResponseMetadata"`
 }
 
 // GetReportListByNextTokenResponse holds response data
@@ -128,7 +127,5 @@ type GetReportListByNextTokenResponse struct {
 		HasNext    bool         `xml:"HasNext"`
 		ReportInfo []ReportInfo `xml:"ReportInfo"`
 	} `xml:"GetReportListByNextTokenResult"`
-	ResponseMetadata struct {
-		RequestID string `xml:"RequestId"`
-	} `xml:"ResponseMetadata"`
+	ResponseMetadata ResponseMetadata `xml:"ResponseMetadata"`
 }
@@ -11,6 +11,11 @@ type GetReportRequestListRequest struct {
 	amazonRequest
 }
 
+// Clone returns a copy of the request that can be modified and executed independently
+func (r *GetReportRequestListRequest) Clone() *GetReportRequestListRequest {
+	return &GetReportRequestListRequest{r.amazonRequest.clone()}
+}
+
 // ReportRequestIDList adds list of report IDs to request - not required
 func (r *GetReportRequestListRequest) ReportRequestIDList(reportIDs []string) *GetReportRequestListRequest {
 	for i, id := range reportIDs {
@@ -29,16 +34,12 @@ func (r *GetReportRequestListRequest) ReportTypeList(reportTypes []string) *GetR
 
 // Do sends request to amazonMWS reports API and returns report request info
 func (r *GetReportRequestListRequest) Do(ctx context.Context) (*GetReportRequestListResponse, error) {
-	respBytes, err := r.client.callAPI(ctx, &r.amazonRequest)
-	if err != nil {
-		return nil, err
-	}
-
 	xmlResponse := &GetReportRequestListResponse{}
-	err = xml.Unmarshal(respBytes, xmlResponse)
+	header, err := r.client.callAPI(ctx, &r.amazonRequest, xmlResponse)
 	if err != nil {
-		return nil, r.client.parseAPIerrors(respBytes)
+		return nil, err
 	}
+	xmlResponse.ResponseMetadata.setHeader(header)
 
 	return xmlResponse, nil
 }
@@ -63,7 +64,5 @@ type GetReportRequestListResponse struct {
 			CompletedDate          string `xml:"CompletedDate"`
 		} `xml:"ReportRequestInfo"`
 	} `xml:"GetReportRequestListResult"`
-	ResponseMetadata struct {
-		RequestID string `xml:"RequestId"`
-	} `xml:"ResponseMetadata"`
+	ResponseMetadata ResponseMetadata `xml:"ResponseMetadata"`
 }
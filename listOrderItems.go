@@ -11,18 +11,19 @@ type ListOrderItemsRequest struct {
 	amazonRequest
 }
 
+// Clone returns a copy of the request that can be modified and executed independently
+func (r *ListOrderItemsRequest) Clone() *ListOrderItemsRequest {
+	return &ListOrderItemsRequest{r.amazonRequest.clone()}
+}
+
 // Do sends request to amazonMWS reports API
 func (r *ListOrderItemsRequest) Do(ctx context.Context) (*ListOrderItemsResponse, error) {
-	respBytes, err := r.client.callAPI(ctx, &r.amazonRequest)
-	if err != nil {
-		return nil, err
-	}
-
 	xmlResponse := &ListOrderItemsResponse{}
-	err = xml.Unmarshal(respBytes, xmlResponse)
+	header, err := r.client.callAPI(ctx, &r.amazonRequest, xmlResponse)
 	if err != nil {
-		return nil, r.client.parseAPIerrors(respBytes)
+		return nil, err
 	}
+	xmlResponse.ResponseMetadata.setHeader(header)
 
 	return xmlResponse, nil
 }
@@ -32,6 +33,7 @@ func (r *ListOrderItemsRequest) DoNext(ctx context.Context, nextToken string) (*
 	nextReq := &amazonRequest{
 		client:   r.client,
 		endpoint: r.endpoint,
+		section:  r.section,
 		method:   r.method,
 		params: url.Values{
 			"Action":    {"ListOrderItemsByNextToken"},
@@ -40,16 +42,12 @@ func (r *ListOrderItemsRequest) DoNext(ctx context.Context, nextToken string) (*
 		},
 	}
 
-	respBytes, err := r.client.callAPI(ctx, nextReq)
-	if err != nil {
-		return nil, err
-	}
-
 	xmlResponse := &ListOrderItemsByNextTokenResponse{}
-	err = xml.Unmarshal(respBytes, xmlResponse)
+	header, err := r.client.callAPI(ctx, nextReq, xmlResponse)
 	if err != nil {
-		return nil, r.client.parseAPIerrors(respBytes)
+		return nil, err
 	}
+	xmlResponse.ResponseMetadata.setHeader(header)
 
 	return xmlResponse, nil
 }
@@ -66,6 +64,9 @@ func (r *ListOrderItemsRequest) DoAll(ctx context.Context) ([]OrderItem, error)
 	nextToken := firstResp.ListOrderItemsResult.NextToken
 	hasNext := (nextToken != "")
 	for hasNext {
+		if err := ctx.Err(); err != nil {
+			return lineItems, err
+		}
 		nextResp, err := r.DoNext(ctx, nextToken)
 		if err != nil {
 			return lineItems, err
@@ -90,9 +91,7 @@ type ListOrderItemsResponse struct {
 			OrderItem []OrderItem `xml:"OrderItem"`
 		} `xml:"OrderItems"`
 	} `xml:"ListOrderItemsResult"`
-	ResponseMetadata struct {
-		RequestID string `xml:"RequestId"`
-	} `xml:"ResponseMetadata"`
+	ResponseMetadata ResponseMetadata `xml:"ResponseMetadata"`
 }
 
 // ListOrderItemsByNextTokenResponse contains next page of line item data
@@ -106,9 +105,7 @@ type ListOrderItemsByNextTokenResponse struct {
 			OrderItem []OrderItem `xml:"OrderItem"`
 		} `xml:"OrderItems"`
 	} `xml:"ListOrderItemsByNextTokenResult"`
-	ResponseMetadata struct {
-		RequestID string `xml:"RequestId"`
-	} `xml:"ResponseMetadata"`
+	ResponseMetadata ResponseMetadata `xml:"ResponseMetadata"`
 }
 
 // OrderItem contains order data - uncomment as needed
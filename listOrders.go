@@ -13,17 +13,29 @@ type ListOrdersRequest struct {
 	amazonRequest
 }
 
+// Clone returns a copy of the request that can be modified and executed independently
+func (r *ListOrdersRequest) Clone() *ListOrdersRequest {
+	return &ListOrdersRequest{r.amazonRequest.clone()}
+}
+
 // CreatedAfter ...
 func (r *ListOrdersRequest) CreatedAfter(t time.Time) *ListOrdersRequest {
 	xmlTime := XMLTimestamp(t)
-	r.params.Add("CreatedAfter", xmlTime)
+	r.params.Set("CreatedAfter", xmlTime)
 	return r
 }
 
 // CreatedBefore ...
 func (r *ListOrdersRequest) CreatedBefore(t time.Time) *ListOrdersRequest {
 	xmlTime := XMLTimestamp(t)
-	r.params.Add("CreatedBefore", xmlTime)
+	r.params.Set("CreatedBefore", xmlTime)
+	return r
+}
+
+// Marketplaces selects the marketplaces of the request, which must all share the client's endpoint.
+// By default the client's own marketplace is used
+func (r *ListOrdersRequest) Marketplaces(marketplaceIDs ...string) *ListOrdersRequest {
+	r.marketplaces = marketplaceIDs
 	return r
 }
 
@@ -38,16 +50,12 @@ func (r *ListOrdersRequest) OrderStatus(status []string) *ListOrdersRequest {
 
 // Do sends request to Amazon API
 func (r *ListOrdersRequest) Do(ctx context.Context) (*ListOrdersResponse, error) {
-	respBytes, err := r.client.callAPI(ctx, &r.amazonRequest)
-	if err != nil {
-		return nil, err
-	}
-
 	xmlResponse := &ListOrdersResponse{}
-	err = xml.Unmarshal(respBytes, xmlResponse)
+	header, err := r.client.callAPI(ctx, &r.amazonRequest, xmlResponse)
 	if err != nil {
-		return nil, r.client.parseAPIerrors(respBytes)
+		return nil, err
 	}
+	xmlResponse.ResponseMetadata.setHeader(header)
 
 	return xmlResponse, nil
 }
@@ -57,6 +65,7 @@ func (r *ListOrdersRequest) DoNext(ctx context.Context, nextToken string) (*List
 	nextReq := &amazonRequest{
 		client:   r.client,
 		endpoint: r.endpoint,
+		section:  r.section,
 		method:   r.method,
 		params: url.Values{
 			"Action":    {"ListOrdersByNextToken"},
@@ -65,16 +74,12 @@ func (r *ListOrdersRequest) DoNext(ctx context.Context, nextToken string) (*List
 		},
 	}
 
-	respBytes, err := r.client.callAPI(ctx, nextReq)
-	if err != nil {
-		return nil, err
-	}
-
 	xmlResponse := &ListOrdersByNextTokenResponse{}
-	err = xml.Unmarshal(respBytes, xmlResponse)
+	header, err := r.client.callAPI(ctx, nextReq, xmlResponse)
 	if err != nil {
-		return nil, r.client.parseAPIerrors(respBytes)
+		return nil, err
 	}
+	xmlResponse.ResponseMetadata.setHeader(header)
 
 	return xmlResponse, nil
 }
@@ -91,6 +96,9 @@ func (r *ListOrdersRequest) DoAll(ctx context.Context) ([]Order, error) {
 	nextToken := firstResp.ListOrdersResult.NextToken
 	hasNext := (nextToken != "")
 	for hasNext {
+		if err := ctx.Err(); err != nil {
+			return orders, err
+		}
 		nextResp, err := r.DoNext(ctx, nextToken)
 		if err != nil {
 			return orders, err
@@ -115,9 +123,7 @@ type ListOrdersResponse struct {
 			Order []Order `xml:"Order"`
 		} `xml:"Orders"`
 	} `xml:"ListOrdersResult"`
-	ResponseMetadata struct {
-		RequestID string `xml:"RequestId"`
-	} `xml:"ResponseMetadata"`
+	ResponseMetadata ResponseMetadata `xml:"ResponseMetadata"`
 }
 
 // ListOrdersByNextTokenResponse holds reponse data for ListOrders call
@@ -134,10 +140,7 @@ type ListOrdersByNextTokenResponse struct {
 			Order []Order `xml:"Order"`
 		} `xml:"Orders"`
 	} `xml:"ListOrdersByNextTokenResult"`
-	ResponseMetadata struct {
-		Text      string `xml:",chardata"`
-		RequestID string `xml:"RequestId"`
-	} `xml:"ResponseMetadata"`
+	ResponseMetadata ResponseMetadata `xml:"ResponseMetadata"`
 }
 
 // Order contains data on a single order
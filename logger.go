@@ -0,0 +1,86 @@
+package amazonmwsapi
+
+import (
+	"context"
+	"log/slog"
+)
+
+// LogLevel is the severity of a log entry
+type LogLevel int
+
+// Log levels used by the AmazonClient
+const (
+	LevelDebug LogLevel = iota
+	LevelInfo
+	LevelWarn
+	LevelError
+)
+
+// Fields holds structured log data, keyed by the Field* constants
+type Fields map[string]interface{}
+
+// Structured field keys used in log entries
+const (
+	FieldOperation  = "operation"
+	FieldSection    = "section"
+	FieldURL        = "url"
+	FieldStatus     = "status"
+	FieldRequestID  = "request_id"
+	FieldDuration   = "duration"
+	FieldAttempt    = "attempt"
+	FieldRetryDelay = "retry_delay"
+	FieldClockSkew  = "clock_skew"
+	FieldError      = "error"
+	FieldBody       = "body"
+)
+
+// Logger receives the log output of the AmazonClient.
+// See NewSlogLogger, and package logrusadapter for logrus
+type Logger interface {
+	Log(ctx context.Context, level LogLevel, msg string, fields Fields)
+}
+
+// LoggerFunc adapts a function to the Logger interface
+type LoggerFunc func(ctx context.Context, level LogLevel, msg string, fields Fields)
+
+// Log implements Logger
+func (f LoggerFunc) Log(ctx context.Context, level LogLevel, msg string, fields Fields) {
+	f(ctx, level, msg, fields)
+}
+
+// NewSlogLogger adapts a *slog.Logger to the Logger interface
+func NewSlogLogger(l *slog.Logger) Logger {
+	return &slogLogger{l: l}
+}
+
+type slogLogger struct {
+	l *slog.Logger
+}
+
+func (s *slogLogger) Log(ctx context.Context, level LogLevel, msg string, fields Fields) {
+	attrs := make([]slog.Attr, 0, len(fields))
+	for k, v := range fields {
+		attrs = append(attrs, slog.Any(k, v))
+	}
+	s.l.LogAttrs(ctx, slogLevel(level), msg, attrs...)
+}
+
+func slogLevel(level LogLevel) slog.Level {
+	switch level {
+	case LevelDebug:
+		return slog.LevelDebug
+	case LevelInfo:
+		return slog.LevelInfo
+	case LevelWarn:
+		return slog.LevelWarn
+	default:
+		return slog.LevelError
+	}
+}
+
+// log sends an entry to the client's Logger, if one is set
+func (c *AmazonClient) log(ctx context.Context, level LogLevel, msg string, fields Fields) {
+	if c.Logger != nil {
+		c.Logger.Log(ctx, level, msg, fields)
+	}
+}
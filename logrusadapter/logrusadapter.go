@@ -0,0 +1,35 @@
+// Package logrusadapter adapts a *logrus.Logger to the amazonmwsapi.Logger interface
+package logrusadapter
+
+import (
+	"context"
+
+	amazonmwsapi "github.com/mike-holberger/amazonmws-go"
+	"github.com/sirupsen/logrus"
+)
+
+// New adapts l to the amazonmwsapi.Logger interface
+func New(l *logrus.Logger) amazonmwsapi.Logger {
+	return &logger{l: l}
+}
+
+type logger struct {
+	l *logrus.Logger
+}
+
+func (a *logger) Log(ctx context.Context, level amazonmwsapi.LogLevel, msg string, fields amazonmwsapi.Fields) {
+	a.l.WithContext(ctx).WithFields(logrus.Fields(fields)).Log(logrusLevel(level), msg)
+}
+
+func logrusLevel(level amazonmwsapi.LogLevel) logrus.Level {
+	switch level {
+	case amazonmwsapi.LevelDebug:
+		return logrus.DebugLevel
+	case amazonmwsapi.LevelInfo:
+		return logrus.InfoLevel
+	case amazonmwsapi.LevelWarn:
+		return logrus.WarnLevel
+	default:
+		return logrus.ErrorLevel
+	}
+}
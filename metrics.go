@@ -0,0 +1,54 @@
+package amazonmwsapi
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Metrics receives measurements of every amzMWS request the client sends.
+// See package mwsprom for a Prometheus collector
+type Metrics interface {
+	// ObserveRequest is called for every HTTP request sent, including retries. status is 0 when
+	// no response was received
+	ObserveRequest(operation string, status int, duration time.Duration)
+	// ObserveThrottled is called for every RequestThrottled or QuotaExceeded response
+	ObserveThrottled(operation string)
+	// ObserveRetry is called before every retry of a failed request
+	ObserveRetry(operation string)
+	// ObserveDownload is called with the number of bytes of a report written by Download, or read
+	// from Stream once it is closed
+	ObserveDownload(operation string, bytes int64)
+	// ObserveUpload is called with the number of bytes of a feed sent to amzMWS
+	ObserveUpload(operation string, bytes int64)
+	// ObserveQuota is called with the x-mws-quota-remaining header of a response. Quotas are
+	// per seller, so seller is the SellerId the request was sent for
+	ObserveQuota(operation, seller string, remaining float64)
+}
+
+// WithMetrics sets the Metrics the client reports to
+func WithMetrics(m Metrics) Option {
+	return func(c *AmazonClient) {
+		c.metrics = m
+	}
+}
+
+// observeResponse reports an attempt's response to request, or its transport error when resp is nil
+func (c *AmazonClient) observeResponse(req *amazonRequest, request *http.Request, resp *http.Response, duration time.Duration) {
+	if c.metrics == nil {
+		return
+	}
+	operation := req.operation()
+	if resp == nil {
+		c.metrics.ObserveRequest(operation, 0, duration)
+		return
+	}
+
+	c.metrics.ObserveRequest(operation, resp.StatusCode, duration)
+	if req.body != nil {
+		c.metrics.ObserveUpload(operation, int64(req.body.Len()))
+	}
+	if remaining, err := strconv.ParseFloat(resp.Header.Get("x-mws-quota-remaining"), 64); err == nil {
+		c.metrics.ObserveQuota(operation, request.URL.Query().Get("SellerId"), remaining)
+	}
+}
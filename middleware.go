@@ -0,0 +1,57 @@
+package amazonmwsapi
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+)
+
+// Call is a single attempt of an amzMWS request, passed down the middleware chain
+type Call struct {
+	// Operation is the amzMWS Action, e.g. "ListOrders"
+	Operation string
+	// Section is the API section, "Orders", "Reports" or "Feeds"
+	Section string
+	// Attempt counts the attempts of the request, starting at 1
+	Attempt int
+	// Params are the request params before credentials, marketplaces, Timestamp and Signature are added
+	Params url.Values
+	// Request is the signed request. Headers may be added, but changing the URL invalidates the signature
+	Request *http.Request
+}
+
+// Handler sends a Call to amzMWS and returns its response, with the body unread
+type Handler func(ctx context.Context, call *Call) (*http.Response, error)
+
+// Middleware wraps a Handler, e.g. to add headers, audit calls or inject faults.
+// A Handler may return a response or error without calling next
+type Middleware func(next Handler) Handler
+
+// Use appends middleware to the chain every request of the client passes through, including
+// retries, report downloads and feed submissions, but not Presign. The first middleware added is the outermost.
+// Use must not be called while the client is in use
+func (c *AmazonClient) Use(middleware ...Middleware) {
+	c.middleware = append(c.middleware, middleware...)
+}
+
+// handler returns the client's middleware chain around the HTTP client
+func (c *AmazonClient) handler() Handler {
+	h := func(ctx context.Context, call *Call) (*http.Response, error) {
+		return c.httpClient.Do(call.Request.WithContext(ctx))
+	}
+	for i := len(c.middleware) - 1; i >= 0; i-- {
+		h = c.middleware[i](h)
+	}
+	return h
+}
+
+// BufferBody reads the body of resp and replaces it with a reader of the returned bytes,
+// so middleware can inspect a response and still pass it on
+func BufferBody(resp *http.Response) ([]byte, error) {
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	return body, err
+}
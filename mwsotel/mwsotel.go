@@ -0,0 +1,78 @@
+// Package mwsotel traces the calls of amazonmwsapi clients with OpenTelemetry
+package mwsotel
+
+import (
+	"context"
+	"time"
+
+	amazonmwsapi "github.com/mike-holberger/amazonmws-go"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracerName is the instrumentation scope of the spans created by the Tracer
+const tracerName = "github.com/mike-holberger/amazonmws-go"
+
+// Span attributes set on every amzMWS call span
+const (
+	AttrOperation    = attribute.Key("mws.operation")
+	AttrMarketplaces = attribute.Key("mws.marketplace_ids")
+	AttrStatus       = attribute.Key("http.response.status_code")
+	AttrRequestID    = attribute.Key("mws.request_id")
+	AttrRetries      = attribute.Key("mws.retry_count")
+	AttrThrottled    = attribute.Key("mws.throttled_count")
+	AttrThrottleWait = attribute.Key("mws.throttle_wait_ms")
+)
+
+// New returns an amazonmwsapi.Tracer creating spans with tp, e.g. otel.GetTracerProvider().
+// Each call is a client span, a child of the span in the context passed to Do, DoAll or Download:
+//
+//	client := amazonmwsapi.NewAmazonClient(creds, "US", nil,
+//		amazonmwsapi.WithTracer(mwsotel.New(otel.GetTracerProvider())))
+func New(tp trace.TracerProvider) amazonmwsapi.Tracer {
+	return &tracer{t: tp.Tracer(tracerName)}
+}
+
+type tracer struct {
+	t trace.Tracer
+}
+
+func (t *tracer) StartCall(ctx context.Context, call amazonmwsapi.CallInfo) (context.Context, amazonmwsapi.CallSpan) {
+	ctx, span := t.t.Start(ctx, call.Section+"/"+call.Operation,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			AttrOperation.String(call.Operation),
+			AttrMarketplaces.StringSlice(call.MarketplaceIDs),
+		))
+	return ctx, &callSpan{span: span}
+}
+
+type callSpan struct {
+	span trace.Span
+}
+
+func (s *callSpan) Response(status int, requestID string) {
+	s.span.SetAttributes(AttrStatus.Int(status), AttrRequestID.String(requestID))
+}
+
+func (s *callSpan) Retry(attempt int, delay time.Duration, err error) {
+	s.span.AddEvent("retry", trace.WithAttributes(
+		attribute.Int("mws.attempt", attempt),
+		attribute.Int64("mws.retry_delay_ms", delay.Milliseconds()),
+		attribute.String("error", err.Error()),
+	))
+}
+
+func (s *callSpan) End(stats amazonmwsapi.CallStats, err error) {
+	s.span.SetAttributes(
+		AttrRetries.Int(stats.Retries),
+		AttrThrottled.Int(stats.Throttled),
+		AttrThrottleWait.Int64(stats.ThrottleWait.Milliseconds()),
+	)
+	if err != nil {
+		s.span.RecordError(err)
+		s.span.SetStatus(codes.Error, err.Error())
+	}
+	s.span.End()
+}
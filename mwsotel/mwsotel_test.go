@@ -0,0 +1,127 @@
+package mwsotel
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	amazonmwsapi "github.com/mike-holberger/amazonmws-go"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// newClient returns a client sending its requests to handler, traced by a recorder
+func newClient(t *testing.T, handler http.HandlerFunc) (*amazonmwsapi.AmazonClient, trace.Tracer, *tracetest.SpanRecorder) {
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	c := amazonmwsapi.NewAmazonClient(amazonmwsapi.Creds{AccessID: "id", AccessKey: "key", Merchant: "seller"}, "US", nil,
+		amazonmwsapi.WithRateLimiter(nil),
+		amazonmwsapi.WithTracer(New(tp)),
+		amazonmwsapi.WithRetryPolicy(amazonmwsapi.ExponentialBackoff{MaxRetries: 1, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond}))
+	c.Region.Endpoint = srv.URL + "/"
+	return c, tp.Tracer("test"), recorder
+}
+
+func attributes(span sdktrace.ReadOnlySpan) map[attribute.Key]attribute.Value {
+	attrs := map[attribute.Key]attribute.Value{}
+	for _, kv := range span.Attributes() {
+		attrs[kv.Key] = kv.Value
+	}
+	return attrs
+}
+
+func TestSpanParenting(t *testing.T) {
+	c, tracer, recorder := newClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("Action") == "ListOrders" {
+			w.Write([]byte(`<ListOrdersResponse><ListOrdersResult><NextToken>next</NextToken></ListOrdersResult></ListOrdersResponse>`))
+			return
+		}
+		w.Write([]byte(`<ListOrdersByNextTokenResponse><ListOrdersByNextTokenResult></ListOrdersByNextTokenResult></ListOrdersByNextTokenResponse>`))
+	})
+
+	ctx, parent := tracer.Start(context.Background(), "parent")
+	if _, err := amazonmwsapi.NewOrdersAPI(c).ListOrders().CreatedAfter(time.Now()).DoAll(ctx); err != nil {
+		t.Fatal(err)
+	}
+	parent.End()
+
+	spans := recorder.Ended()
+	if len(spans) != 3 {
+		t.Fatalf("got %d spans, want 2 calls and their parent", len(spans))
+	}
+	for i, name := range []string{"Orders/ListOrders", "Orders/ListOrdersByNextToken"} {
+		span := spans[i]
+		if span.Name() != name || span.SpanKind() != trace.SpanKindClient {
+			t.Errorf("span %d: got %s %s, want client span %s", i, span.SpanKind(), span.Name(), name)
+		}
+		if span.Parent().SpanID() != parent.SpanContext().SpanID() {
+			t.Errorf("span %s: not a child of the caller's span", span.Name())
+		}
+		if got := attributes(span)[AttrMarketplaces].AsStringSlice(); len(got) != 1 || got[0] != "ATVPDKIKX0DER" {
+			t.Errorf("span %s: got marketplaces %v", span.Name(), got)
+		}
+	}
+}
+
+func TestRetryAndThrottleAttributes(t *testing.T) {
+	requests := 0
+	c, _, recorder := newClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			w.Write([]byte(`<ErrorResponse><Error><Type>Sender</Type><Code>RequestThrottled</Code></Error></ErrorResponse>`))
+			return
+		}
+		w.Header().Set("x-mws-request-id", "request-2")
+		w.Write([]byte(`<GetOrderResponse></GetOrderResponse>`))
+	})
+
+	if _, err := amazonmwsapi.NewOrdersAPI(c).GetOrder([]string{"1"}).Do(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("got %d spans, want 1 for the call and its retry", len(spans))
+	}
+	span := spans[0]
+	attrs := attributes(span)
+	if attrs[AttrRetries].AsInt64() != 1 || attrs[AttrThrottled].AsInt64() != 1 {
+		t.Errorf("got %v retries and %v throttled, want 1 and 1", attrs[AttrRetries], attrs[AttrThrottled])
+	}
+	if attrs[AttrStatus].AsInt64() != 200 || attrs[AttrRequestID].AsString() != "request-2" {
+		t.Errorf("got status %v request ID %v, want the last response's", attrs[AttrStatus], attrs[AttrRequestID])
+	}
+	if _, ok := attrs[AttrThrottleWait]; !ok {
+		t.Errorf("no %s attribute", AttrThrottleWait)
+	}
+	if events := span.Events(); len(events) != 1 || events[0].Name != "retry" {
+		t.Errorf("got events %v, want 1 retry", events)
+	}
+	if span.Status().Code != codes.Unset {
+		t.Errorf("got status %v for a successful call", span.Status())
+	}
+}
+
+func TestFailedCallStatus(t *testing.T) {
+	c, _, recorder := newClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`<ErrorResponse><Error><Type>Sender</Type><Code>InvalidParameterValue</Code></Error></ErrorResponse>`))
+	})
+
+	if _, err := amazonmwsapi.NewOrdersAPI(c).GetOrder([]string{"1"}).Do(context.Background()); err == nil {
+		t.Fatal("no error for a 400 response")
+	}
+	span := recorder.Ended()[0]
+	if span.Status().Code != codes.Error || attributes(span)[AttrRetries].AsInt64() != 0 {
+		t.Errorf("got status %v and %v retries, want an error without retries", span.Status(), attributes(span)[AttrRetries])
+	}
+}
@@ -0,0 +1,124 @@
+// Package mwsprom exports the metrics of amazonmwsapi clients to Prometheus
+package mwsprom
+
+import (
+	"strconv"
+	"time"
+
+	amazonmwsapi "github.com/mike-holberger/amazonmws-go"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Collector implements amazonmwsapi.Metrics as a prometheus.Collector. One Collector can be
+// shared by several clients, e.g. all clients of a ClientPool:
+//
+//	metrics := mwsprom.New("")
+//	registry.MustRegister(metrics)
+//	client := amazonmwsapi.NewAmazonClient(creds, "US", nil, amazonmwsapi.WithMetrics(metrics))
+type Collector struct {
+	requests   *prometheus.CounterVec
+	duration   *prometheus.HistogramVec
+	throttled  *prometheus.CounterVec
+	retries    *prometheus.CounterVec
+	downloaded *prometheus.CounterVec
+	uploaded   *prometheus.CounterVec
+	quota      *prometheus.GaugeVec
+}
+
+// New returns a Collector whose metric names are prefixed with namespace, "mws" when empty
+func New(namespace string) *Collector {
+	if namespace == "" {
+		namespace = "mws"
+	}
+	operation := []string{"operation"}
+	return &Collector{
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "requests_total",
+			Help:      "HTTP requests sent to amzMWS, including retries, by operation and status (0 without response).",
+		}, []string{"operation", "status"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "request_duration_seconds",
+			Help:      "Latency of HTTP requests to amzMWS by operation and status.",
+			Buckets:   prometheus.ExponentialBuckets(0.05, 2, 10),
+		}, []string{"operation", "status"}),
+		throttled: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "throttled_total",
+			Help:      "RequestThrottled and QuotaExceeded responses by operation.",
+		}, operation),
+		retries: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "retries_total",
+			Help:      "Retries of failed requests by operation.",
+		}, operation),
+		downloaded: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "downloaded_bytes_total",
+			Help:      "Report bytes downloaded or streamed by operation.",
+		}, operation),
+		uploaded: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "uploaded_bytes_total",
+			Help:      "Feed bytes sent to amzMWS by operation.",
+		}, operation),
+		quota: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "quota_remaining",
+			Help:      "Last observed x-mws-quota-remaining header by operation and seller.",
+		}, []string{"operation", "seller"}),
+	}
+}
+
+var _ amazonmwsapi.Metrics = (*Collector)(nil)
+
+func (c *Collector) collectors() []prometheus.Collector {
+	return []prometheus.Collector{c.requests, c.duration, c.throttled, c.retries, c.downloaded, c.uploaded, c.quota}
+}
+
+// Describe implements prometheus.Collector
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	for _, collector := range c.collectors() {
+		collector.Describe(ch)
+	}
+}
+
+// Collect implements prometheus.Collector
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	for _, collector := range c.collectors() {
+		collector.Collect(ch)
+	}
+}
+
+// ObserveRequest implements amazonmwsapi.Metrics
+func (c *Collector) ObserveRequest(operation string, status int, duration time.Duration) {
+	code := strconv.Itoa(status)
+	c.requests.WithLabelValues(operation, code).Inc()
+	c.duration.WithLabelValues(operation, code).Observe(duration.Seconds())
+}
+
+// ObserveThrottled implements amazonmwsapi.Metrics
+func (c *Collector) ObserveThrottled(operation string) {
+	c.throttled.WithLabelValues(operation).Inc()
+}
+
+// ObserveRetry implements amazonmwsapi.Metrics
+func (c *Collector) ObserveRetry(operation string) {
+	c.retries.WithLabelValues(operation).Inc()
+}
+
+// ObserveDownload implements amazonmwsapi.Metrics
+func (c *Collector) ObserveDownload(operation string, bytes int64) {
+	c.downloaded.WithLabelValues(operation).Add(float64(bytes))
+}
+
+// ObserveUpload implements amazonmwsapi.Metrics
+func (c *Collector) ObserveUpload(operation string, bytes int64) {
+	c.uploaded.WithLabelValues(operation).Add(float64(bytes))
+}
+
+// ObserveQuota implements amazonmwsapi.Metrics
+func (c *Collector) ObserveQuota(operation, seller string, remaining float64) {
+	c.quota.WithLabelValues(operation, seller).Set(remaining)
+}
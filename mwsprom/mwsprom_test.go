@@ -0,0 +1,97 @@
+package mwsprom
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	amazonmwsapi "github.com/mike-holberger/amazonmws-go"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+// newServer returns an amzMWS stand-in that throttles the first request, then reports a
+// remaining quota of 10 for seller "A" and 20 for any other seller
+func newServer(t *testing.T) *httptest.Server {
+	requests := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			w.Write([]byte(`<ErrorResponse><Error><Type>Sender</Type><Code>RequestThrottled</Code></Error></ErrorResponse>`))
+			return
+		}
+		quota := "20"
+		if r.URL.Query().Get("SellerId") == "A" {
+			quota = "10"
+		}
+		w.Header().Set("x-mws-quota-remaining", quota)
+		if r.URL.Query().Get("Action") == "GetReport" {
+			w.Write([]byte("abcdef"))
+			return
+		}
+		w.Write([]byte(`<ListOrdersResponse></ListOrdersResponse>`))
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func newClient(srv *httptest.Server, seller string, metrics *Collector) *amazonmwsapi.AmazonClient {
+	c := amazonmwsapi.NewAmazonClient(amazonmwsapi.Creds{AccessID: "id", AccessKey: "key", Merchant: seller}, "US", nil,
+		amazonmwsapi.WithRateLimiter(nil),
+		amazonmwsapi.WithMetrics(metrics),
+		amazonmwsapi.WithRetryPolicy(amazonmwsapi.ExponentialBackoff{MaxRetries: 1, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond}))
+	c.Region.Endpoint = srv.URL + "/"
+	return c
+}
+
+func TestCollector(t *testing.T) {
+	srv := newServer(t)
+	metrics := New("")
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(metrics)
+	ctx := context.Background()
+
+	a, b := newClient(srv, "A", metrics), newClient(srv, "B", metrics)
+	if _, err := amazonmwsapi.NewOrdersAPI(a).ListOrders().CreatedAfter(time.Now()).Do(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := amazonmwsapi.NewOrdersAPI(b).ListOrders().CreatedAfter(time.Now()).Do(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := amazonmwsapi.NewReportsAPI(a).GetReport("1").Download(ctx, filepath.Join(t.TempDir(), "report")); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		got  prometheus.Collector
+		want float64
+	}{
+		{"throttled request", metrics.requests.WithLabelValues("ListOrders", "503"), 1},
+		{"successful requests", metrics.requests.WithLabelValues("ListOrders", "200"), 2},
+		{"throttled", metrics.throttled.WithLabelValues("ListOrders"), 1},
+		{"retries", metrics.retries.WithLabelValues("ListOrders"), 1},
+		{"downloaded", metrics.downloaded.WithLabelValues("GetReport"), 6},
+	}
+	for _, tt := range tests {
+		if got := testutil.ToFloat64(tt.got); got != tt.want {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+		}
+	}
+
+	want := `
+# HELP mws_quota_remaining Last observed x-mws-quota-remaining header by operation and seller.
+# TYPE mws_quota_remaining gauge
+mws_quota_remaining{operation="GetReport",seller="A"} 10
+mws_quota_remaining{operation="ListOrders",seller="A"} 10
+mws_quota_remaining{operation="ListOrders",seller="B"} 20
+`
+	if err := testutil.GatherAndCompare(registry, strings.NewReader(want), "mws_quota_remaining"); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,215 @@
+package mwstest
+
+import (
+	"bytes"
+	"crypto/md5"
+	"encoding/base64"
+	"encoding/xml"
+	"io"
+	"net/http"
+	"time"
+
+	amazonmwsapi "github.com/mike-holberger/amazonmws-go"
+)
+
+// Feed is a feed submitted to the server. Feeds are processed as soon as they are submitted
+type Feed struct {
+	SubmissionID string
+	FeedType     string
+	Body         []byte
+	Submitted    time.Time
+
+	// MerchantIdentifier, MessageType and the number of Messages are parsed from the feed body
+	MerchantIdentifier string
+	MessageType        string
+	Messages           int
+	// Error is the fatal processing error of the feed, if any
+	Error string
+}
+
+// Feeds returns the feeds submitted so far
+func (s *Server) Feeds() []Feed {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	feeds := make([]Feed, len(s.feeds))
+	for i, f := range s.feeds {
+		feeds[i] = *f
+	}
+	return feeds
+}
+
+type feedSubmissionInfo struct {
+	FeedSubmissionID        string `xml:"FeedSubmissionId"`
+	FeedType                string `xml:"FeedType"`
+	SubmittedDate           string `xml:"SubmittedDate"`
+	FeedProcessingStatus    string `xml:"FeedProcessingStatus"`
+	StartedProcessingDate   string `xml:"StartedProcessingDate,omitempty"`
+	CompletedProcessingDate string `xml:"CompletedProcessingDate,omitempty"`
+}
+
+// feedEnvelope is the part of a feed body read by the server
+type feedEnvelope struct {
+	XMLName            xml.Name   `xml:"AmazonEnvelope"`
+	MerchantIdentifier string     `xml:"Header>MerchantIdentifier"`
+	MessageType        string     `xml:"MessageType"`
+	Messages           []struct{} `xml:"Message"`
+}
+
+// processingReport is the AmazonEnvelope returned by GetFeedSubmissionResult
+type processingReport struct {
+	XMLName                   xml.Name `xml:"AmazonEnvelope"`
+	Xsi                       string   `xml:"xmlns:xsi,attr"`
+	NoNamespaceSchemaLocation string   `xml:"xsi:noNamespaceSchemaLocation,attr"`
+	DocumentVersion           string   `xml:"Header>DocumentVersion"`
+	MerchantIdentifier        string   `xml:"Header>MerchantIdentifier"`
+	MessageType               string   `xml:"MessageType"`
+	Message                   struct {
+		MessageID        int `xml:"MessageID"`
+		ProcessingReport struct {
+			DocumentTransactionID string `xml:"DocumentTransactionID"`
+			StatusCode            string `xml:"StatusCode"`
+			ProcessingSummary     struct {
+				MessagesProcessed   int `xml:"MessagesProcessed"`
+				MessagesSuccessful  int `xml:"MessagesSuccessful"`
+				MessagesWithError   int `xml:"MessagesWithError"`
+				MessagesWithWarning int `xml:"MessagesWithWarning"`
+			} `xml:"ProcessingSummary"`
+			Result []processingResult `xml:"Result"`
+		} `xml:"ProcessingReport"`
+	} `xml:"Message"`
+}
+
+type processingResult struct {
+	MessageID         int    `xml:"MessageID"`
+	ResultCode        string `xml:"ResultCode"`
+	ResultMessageCode int    `xml:"ResultMessageCode"`
+	ResultDescription string `xml:"ResultDescription"`
+}
+
+func (f *Feed) info() feedSubmissionInfo {
+	submitted := amazonmwsapi.XMLTimestamp(f.Submitted)
+	return feedSubmissionInfo{
+		FeedSubmissionID:        f.SubmissionID,
+		FeedType:                f.FeedType,
+		SubmittedDate:           submitted,
+		FeedProcessingStatus:    statusDone,
+		StartedProcessingDate:   submitted,
+		CompletedProcessingDate: submitted,
+	}
+}
+
+// submitFeed verifies the ContentMD5Value of the feed and processes it
+func (s *Server) submitFeed(c *call) *mwsError {
+	feedType := c.params.Get("FeedType")
+	if feedType == "" {
+		return missingParameter("FeedType")
+	}
+	contentMD5 := c.params.Get("ContentMD5Value")
+	if contentMD5 == "" {
+		return missingParameter("ContentMD5Value")
+	}
+	body, err := io.ReadAll(c.r.Body)
+	if err != nil {
+		return newError(http.StatusBadRequest, "InvalidParameterValue", "Unable to read feed: %s", err)
+	}
+	sum := md5.Sum(body)
+	if base64.StdEncoding.EncodeToString(sum[:]) != contentMD5 {
+		return newError(http.StatusBadRequest, "ContentMD5DoesNotMatch",
+			"the Content-MD5 HTTP header you passed for your feed did not match the Content-MD5 we calculated for your feed")
+	}
+
+	f := &Feed{
+		SubmissionID: s.newID("feed"),
+		FeedType:     feedType,
+		Body:         body,
+		Submitted:    s.Clock(),
+	}
+	envelope := feedEnvelope{}
+	if err := xml.NewDecoder(bytes.NewReader(body)).Decode(&envelope); err != nil {
+		f.Error = "XML Parsing Fatal Error: " + err.Error()
+	} else {
+		f.MerchantIdentifier = envelope.MerchantIdentifier
+		f.MessageType = envelope.MessageType
+		f.Messages = len(envelope.Messages)
+		if f.MerchantIdentifier != c.seller.Merchant {
+			f.Error = "The merchant identifier " + f.MerchantIdentifier + " does not match the seller " + c.seller.Merchant
+		}
+	}
+	s.feeds = append(s.feeds, f)
+
+	info := f.info()
+	info.FeedProcessingStatus = statusSubmitted
+	info.StartedProcessingDate, info.CompletedProcessingDate = "", ""
+	return s.writeResult(c, struct {
+		FeedSubmissionInfo feedSubmissionInfo `xml:"FeedSubmissionInfo"`
+	}{info})
+}
+
+func (s *Server) getFeedSubmissionList(c *call) *mwsError {
+	ids := listParam(c.params, "FeedSubmissionIdList.Id")
+	types := listParam(c.params, "FeedTypeList.Type")
+
+	result := struct {
+		HasNext            bool                 `xml:"HasNext"`
+		FeedSubmissionInfo []feedSubmissionInfo `xml:"FeedSubmissionInfo"`
+	}{}
+	for _, f := range s.feeds {
+		if (len(ids) > 0 && !contains(ids, f.SubmissionID)) || (len(types) > 0 && !contains(types, f.FeedType)) {
+			continue
+		}
+		result.FeedSubmissionInfo = append(result.FeedSubmissionInfo, f.info())
+	}
+	return s.writeResult(c, result)
+}
+
+// getFeedSubmissionResult writes the processing report of a feed, with its MD5 sum in the
+// Content-MD5 header
+func (s *Server) getFeedSubmissionResult(c *call) *mwsError {
+	id := c.params.Get("FeedSubmissionId")
+	if id == "" {
+		return missingParameter("FeedSubmissionId")
+	}
+	var f *Feed
+	for _, feed := range s.feeds {
+		if feed.SubmissionID == id {
+			f = feed
+		}
+	}
+	if f == nil {
+		return invalidParameter("FeedSubmissionId", id)
+	}
+
+	report := processingReport{
+		Xsi:                       "http://www.w3.org/2001/XMLSchema-instance",
+		NoNamespaceSchemaLocation: "amzn-envelope.xsd",
+		DocumentVersion:           "1.02",
+		MerchantIdentifier:        f.MerchantIdentifier,
+		MessageType:               "ProcessingReport",
+	}
+	report.Message.MessageID = 1
+	processing := &report.Message.ProcessingReport
+	processing.DocumentTransactionID = f.SubmissionID
+	processing.StatusCode = "Complete"
+	if f.Error != "" {
+		processing.Result = []processingResult{{
+			ResultCode:        "Error",
+			ResultMessageCode: 5000,
+			ResultDescription: f.Error,
+		}}
+	} else {
+		processing.ProcessingSummary.MessagesProcessed = f.Messages
+		processing.ProcessingSummary.MessagesSuccessful = f.Messages
+	}
+
+	body := &bytes.Buffer{}
+	body.WriteString(xml.Header)
+	if err := xml.NewEncoder(body).Encode(report); err != nil {
+		return newError(http.StatusInternalServerError, "InternalError", "%s", err)
+	}
+	sum := md5.Sum(body.Bytes())
+	c.w.Header().Set("Content-Type", "text/xml")
+	c.w.Header().Set("Content-MD5", base64.StdEncoding.EncodeToString(sum[:]))
+	c.w.WriteHeader(http.StatusOK)
+	c.w.Write(body.Bytes())
+	return nil
+}
@@ -0,0 +1,213 @@
+package mwstest
+
+import (
+	"net/http"
+	"time"
+
+	amazonmwsapi "github.com/mike-holberger/amazonmws-go"
+)
+
+// maxOrderIDs is the most orders GetOrder accepts per call
+const maxOrderIDs = 50
+
+// Order is an order seeded into the server
+type Order struct {
+	AmazonOrderID string
+	PurchaseDate  time.Time
+	// LastUpdateDate defaults to PurchaseDate
+	LastUpdateDate time.Time
+	// OrderStatus defaults to "Unshipped"
+	OrderStatus string
+	// MarketplaceID restricts the order to one marketplace; when empty it is listed for all
+	MarketplaceID string
+	BuyerEmail    string
+	Items         []OrderItem
+}
+
+// OrderItem is a line item of a seeded Order
+type OrderItem struct {
+	OrderItemID     string `xml:"OrderItemId"`
+	SellerSKU       string `xml:"SellerSKU"`
+	QuantityOrdered int    `xml:"QuantityOrdered"`
+}
+
+// AddOrders seeds orders, listed in the order they were added
+func (s *Server) AddOrders(orders ...Order) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, o := range orders {
+		if o.LastUpdateDate.IsZero() {
+			o.LastUpdateDate = o.PurchaseDate
+		}
+		if o.OrderStatus == "" {
+			o.OrderStatus = "Unshipped"
+		}
+		s.orders = append(s.orders, o)
+	}
+}
+
+type orderXML struct {
+	AmazonOrderID  string `xml:"AmazonOrderId"`
+	PurchaseDate   string `xml:"PurchaseDate"`
+	LastUpdateDate string `xml:"LastUpdateDate"`
+	OrderStatus    string `xml:"OrderStatus"`
+	MarketplaceID  string `xml:"MarketplaceId,omitempty"`
+	BuyerEmail     string `xml:"BuyerEmail,omitempty"`
+}
+
+type ordersResult struct {
+	NextToken         string     `xml:"NextToken,omitempty"`
+	CreatedBefore     string     `xml:"CreatedBefore,omitempty"`
+	LastUpdatedBefore string     `xml:"LastUpdatedBefore,omitempty"`
+	Orders            []orderXML `xml:"Orders>Order"`
+}
+
+type orderItemsResult struct {
+	NextToken     string      `xml:"NextToken,omitempty"`
+	AmazonOrderID string      `xml:"AmazonOrderId"`
+	OrderItems    []OrderItem `xml:"OrderItems>OrderItem"`
+}
+
+func newOrderXML(o Order) orderXML {
+	return orderXML{
+		AmazonOrderID:  o.AmazonOrderID,
+		PurchaseDate:   amazonmwsapi.XMLTimestamp(o.PurchaseDate),
+		LastUpdateDate: amazonmwsapi.XMLTimestamp(o.LastUpdateDate),
+		OrderStatus:    o.OrderStatus,
+		MarketplaceID:  o.MarketplaceID,
+		BuyerEmail:     o.BuyerEmail,
+	}
+}
+
+func (s *Server) listOrders(c *call) *mwsError {
+	marketplaces := listParam(c.params, "MarketplaceId.Id")
+	if len(marketplaces) == 0 {
+		return missingParameter("MarketplaceId.Id.1")
+	}
+	createdAfter, err := timeParam(c.params, "CreatedAfter")
+	if err != nil {
+		return err
+	}
+	createdBefore, err := timeParam(c.params, "CreatedBefore")
+	if err != nil {
+		return err
+	}
+	updatedAfter, err := timeParam(c.params, "LastUpdatedAfter")
+	if err != nil {
+		return err
+	}
+	updatedBefore, err := timeParam(c.params, "LastUpdatedBefore")
+	if err != nil {
+		return err
+	}
+	if createdAfter.IsZero() == updatedAfter.IsZero() {
+		return newError(http.StatusBadRequest, "InvalidParameterValue",
+			"Exactly one of CreatedAfter and LastUpdatedAfter must be specified")
+	}
+	statuses := listParam(c.params, "OrderStatus.Status")
+	size, err := s.pageSize(c, "MaxResultsPerPage", 100)
+	if err != nil {
+		return err
+	}
+
+	var orders []Order
+	for _, o := range s.orders {
+		switch {
+		case o.MarketplaceID != "" && !contains(marketplaces, o.MarketplaceID),
+			!createdAfter.IsZero() && o.PurchaseDate.Before(createdAfter),
+			!createdBefore.IsZero() && !o.PurchaseDate.Before(createdBefore),
+			!updatedAfter.IsZero() && o.LastUpdateDate.Before(updatedAfter),
+			!updatedBefore.IsZero() && !o.LastUpdateDate.Before(updatedBefore),
+			len(statuses) > 0 && !contains(statuses, o.OrderStatus):
+			continue
+		}
+		orders = append(orders, o)
+	}
+
+	result := s.ordersPage(orders, size)
+	if !createdAfter.IsZero() {
+		result.CreatedBefore = amazonmwsapi.XMLTimestamp(s.Clock())
+	} else {
+		result.LastUpdatedBefore = amazonmwsapi.XMLTimestamp(s.Clock())
+	}
+	return s.writeResult(c, result)
+}
+
+func (s *Server) listOrdersByNextToken(c *call) *mwsError {
+	p, err := s.takePage(c)
+	if err != nil {
+		return err
+	}
+	return s.writeResult(c, s.ordersPage(p.orders, p.size))
+}
+
+// ordersPage returns the first size orders, keeping the rest under a NextToken
+func (s *Server) ordersPage(orders []Order, size int) ordersResult {
+	result := ordersResult{}
+	if len(orders) > size {
+		result.NextToken = s.nextToken(&page{
+			action: "ListOrdersByNextToken",
+			size:   size,
+			orders: orders[size:],
+		})
+		orders = orders[:size]
+	}
+	for _, o := range orders {
+		result.Orders = append(result.Orders, newOrderXML(o))
+	}
+	return result
+}
+
+func (s *Server) getOrder(c *call) *mwsError {
+	ids := listParam(c.params, "AmazonOrderId.Id")
+	if len(ids) == 0 {
+		return missingParameter("AmazonOrderId.Id.1")
+	}
+	if len(ids) > maxOrderIDs {
+		return newError(http.StatusBadRequest, "InvalidParameterValue", "At most %d AmazonOrderIds are allowed", maxOrderIDs)
+	}
+
+	result := ordersResult{}
+	for _, o := range s.orders {
+		if contains(ids, o.AmazonOrderID) {
+			result.Orders = append(result.Orders, newOrderXML(o))
+		}
+	}
+	return s.writeResult(c, result)
+}
+
+func (s *Server) listOrderItems(c *call) *mwsError {
+	id := c.params.Get("AmazonOrderId")
+	if id == "" {
+		return missingParameter("AmazonOrderId")
+	}
+	for _, o := range s.orders {
+		if o.AmazonOrderID == id {
+			return s.writeResult(c, s.orderItemsPage(id, o.Items))
+		}
+	}
+	return invalidParameter("AmazonOrderId", id)
+}
+
+func (s *Server) listOrderItemsByNextToken(c *call) *mwsError {
+	p, err := s.takePage(c)
+	if err != nil {
+		return err
+	}
+	return s.writeResult(c, s.orderItemsPage(p.orderID, p.items))
+}
+
+// orderItemsPage returns the first PageSize items, keeping the rest under a NextToken
+func (s *Server) orderItemsPage(orderID string, items []OrderItem) orderItemsResult {
+	result := orderItemsResult{AmazonOrderID: orderID}
+	if size := s.PageSize; size > 0 && len(items) > size {
+		result.NextToken = s.nextToken(&page{
+			action:  "ListOrderItemsByNextToken",
+			orderID: orderID,
+			items:   items[size:],
+		})
+		items = items[:size]
+	}
+	result.OrderItems = items
+	return result
+}
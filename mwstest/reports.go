@@ -0,0 +1,263 @@
+package mwstest
+
+import (
+	"crypto/md5"
+	"encoding/base64"
+	"net/http"
+	"strconv"
+	"time"
+
+	amazonmwsapi "github.com/mike-holberger/amazonmws-go"
+)
+
+// Report processing statuses, in the order a report request goes through them
+const (
+	statusSubmitted  = "_SUBMITTED_"
+	statusInProgress = "_IN_PROGRESS_"
+	statusDone       = "_DONE_"
+)
+
+// maxReportCount is the largest MaxCount of GetReportList
+const maxReportCount = 100
+
+type reportRequest struct {
+	id         string
+	reportType string
+	submitted  time.Time
+	startDate  string
+	endDate    string
+	polls      int
+	report     *report
+}
+
+type report struct {
+	id           string
+	reportType   string
+	requestID    string
+	available    time.Time
+	acknowledged bool
+	content      []byte
+}
+
+type reportRequestInfo struct {
+	ReportRequestID        string `xml:"ReportRequestId"`
+	ReportType             string `xml:"ReportType"`
+	StartDate              string `xml:"StartDate,omitempty"`
+	EndDate                string `xml:"EndDate,omitempty"`
+	Scheduled              bool   `xml:"Scheduled"`
+	SubmittedDate          string `xml:"SubmittedDate"`
+	ReportProcessingStatus string `xml:"ReportProcessingStatus"`
+	GeneratedReportID      string `xml:"GeneratedReportId,omitempty"`
+}
+
+type reportInfo struct {
+	ReportID        string `xml:"ReportId"`
+	ReportType      string `xml:"ReportType"`
+	ReportRequestID string `xml:"ReportRequestId"`
+	AvailableDate   string `xml:"AvailableDate"`
+	Acknowledged    bool   `xml:"Acknowledged"`
+}
+
+type reportListResult struct {
+	NextToken  string       `xml:"NextToken,omitempty"`
+	HasNext    bool         `xml:"HasNext"`
+	ReportInfo []reportInfo `xml:"ReportInfo"`
+}
+
+// AddReport sets the content of the reports generated for reportType. Reports of types without
+// content are empty
+func (s *Server) AddReport(reportType string, content []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.reportContent[reportType] = content
+}
+
+// status returns the processing status of r after polls GetReportRequestList calls
+func (r *reportRequest) status(polls int) string {
+	switch {
+	case polls <= 0 || r.polls >= 2*polls:
+		return statusDone
+	case r.polls >= polls:
+		return statusInProgress
+	default:
+		return statusSubmitted
+	}
+}
+
+func (r *reportRequest) info(status string) reportRequestInfo {
+	info := reportRequestInfo{
+		ReportRequestID:        r.id,
+		ReportType:             r.reportType,
+		StartDate:              r.startDate,
+		EndDate:                r.endDate,
+		SubmittedDate:          amazonmwsapi.XMLTimestamp(r.submitted),
+		ReportProcessingStatus: status,
+	}
+	if r.report != nil {
+		info.GeneratedReportID = r.report.id
+	}
+	return info
+}
+
+func (r *report) info() reportInfo {
+	return reportInfo{
+		ReportID:        r.id,
+		ReportType:      r.reportType,
+		ReportRequestID: r.requestID,
+		AvailableDate:   amazonmwsapi.XMLTimestamp(r.available),
+		Acknowledged:    r.acknowledged,
+	}
+}
+
+func (s *Server) requestReport(c *call) *mwsError {
+	reportType := c.params.Get("ReportType")
+	if reportType == "" {
+		return missingParameter("ReportType")
+	}
+
+	r := &reportRequest{
+		id:         s.newID("report-request"),
+		reportType: reportType,
+		submitted:  s.Clock(),
+		startDate:  c.params.Get("StartDate"),
+		endDate:    c.params.Get("EndDate"),
+	}
+	s.reportRequests = append(s.reportRequests, r)
+
+	return s.writeResult(c, struct {
+		ReportRequestInfo reportRequestInfo `xml:"ReportRequestInfo"`
+	}{r.info(statusSubmitted)})
+}
+
+// getReportRequestList lists report requests, each call moving them a step towards _DONE_
+func (s *Server) getReportRequestList(c *call) *mwsError {
+	ids := listParam(c.params, "ReportRequestIdList.Id")
+	types := listParam(c.params, "ReportTypeList.Type")
+	statuses := listParam(c.params, "ReportProcessingStatusList.Status")
+
+	result := struct {
+		HasNext           bool                `xml:"HasNext"`
+		ReportRequestInfo []reportRequestInfo `xml:"ReportRequestInfo"`
+	}{}
+	for _, r := range s.reportRequests {
+		status := r.status(s.ReportPolls)
+		r.polls++
+		if status == statusDone && r.report == nil {
+			r.report = &report{
+				id:         s.newID("report"),
+				reportType: r.reportType,
+				requestID:  r.id,
+				available:  s.Clock(),
+				content:    s.reportContent[r.reportType],
+			}
+			s.reports = append(s.reports, r.report)
+		}
+
+		if (len(ids) > 0 && !contains(ids, r.id)) ||
+			(len(types) > 0 && !contains(types, r.reportType)) ||
+			(len(statuses) > 0 && !contains(statuses, status)) {
+			continue
+		}
+		result.ReportRequestInfo = append(result.ReportRequestInfo, r.info(status))
+	}
+	return s.writeResult(c, result)
+}
+
+func (s *Server) getReportList(c *call) *mwsError {
+	ids := listParam(c.params, "ReportRequestIdList.Id")
+	types := listParam(c.params, "ReportTypeList.Type")
+	acknowledged := c.params.Get("Acknowledged")
+	if acknowledged != "" && acknowledged != "true" && acknowledged != "false" {
+		return invalidParameter("Acknowledged", acknowledged)
+	}
+	size, err := s.pageSize(c, "MaxCount", maxReportCount)
+	if err != nil {
+		return err
+	}
+
+	var reports []*report
+	for _, r := range s.reports {
+		if (len(ids) > 0 && !contains(ids, r.requestID)) ||
+			(len(types) > 0 && !contains(types, r.reportType)) ||
+			(acknowledged != "" && acknowledged != strconv.FormatBool(r.acknowledged)) {
+			continue
+		}
+		reports = append(reports, r)
+	}
+	return s.writeResult(c, s.reportsPage(reports, size))
+}
+
+func (s *Server) getReportListByNextToken(c *call) *mwsError {
+	p, err := s.takePage(c)
+	if err != nil {
+		return err
+	}
+	return s.writeResult(c, s.reportsPage(p.reports, p.size))
+}
+
+// reportsPage returns the first size reports, keeping the rest under a NextToken
+func (s *Server) reportsPage(reports []*report, size int) reportListResult {
+	result := reportListResult{}
+	if len(reports) > size {
+		result.HasNext = true
+		result.NextToken = s.nextToken(&page{
+			action:  "GetReportListByNextToken",
+			size:    size,
+			reports: reports[size:],
+		})
+		reports = reports[:size]
+	}
+	for _, r := range reports {
+		result.ReportInfo = append(result.ReportInfo, r.info())
+	}
+	return result
+}
+
+func (s *Server) findReport(id string) *report {
+	for _, r := range s.reports {
+		if r.id == id {
+			return r
+		}
+	}
+	return nil
+}
+
+// getReport writes the report content, with its MD5 sum in the Content-MD5 header
+func (s *Server) getReport(c *call) *mwsError {
+	id := c.params.Get("ReportId")
+	if id == "" {
+		return missingParameter("ReportId")
+	}
+	r := s.findReport(id)
+	if r == nil {
+		return invalidParameter("ReportId", id)
+	}
+
+	sum := md5.Sum(r.content)
+	c.w.Header().Set("Content-Type", "text/plain;charset=UTF-8")
+	c.w.Header().Set("Content-MD5", base64.StdEncoding.EncodeToString(sum[:]))
+	c.w.WriteHeader(http.StatusOK)
+	c.w.Write(r.content)
+	return nil
+}
+
+func (s *Server) updateReportAcknowledgements(c *call) *mwsError {
+	ids := listParam(c.params, "ReportIdList.Id")
+	if len(ids) == 0 {
+		return missingParameter("ReportIdList.Id.1")
+	}
+	acknowledged := c.params.Get("Acknowledged") != "false"
+
+	result := struct {
+		Count      int          `xml:"Count"`
+		ReportInfo []reportInfo `xml:"ReportInfo"`
+	}{}
+	for _, id := range ids {
+		if r := s.findReport(id); r != nil {
+			r.acknowledged = acknowledged
+			result.ReportInfo = append(result.ReportInfo, r.info())
+		}
+	}
+	result.Count = len(result.ReportInfo)
+	return s.writeResult(c, result)
+}
@@ -0,0 +1,421 @@
+// Package mwstest provides an in-process fake amzMWS server for tests.
+//
+// The server emulates the Orders, Reports and Feeds operations called by amazonmwsapi: it verifies
+// Signature Version 2 against the credentials it was started with, serves seeded orders with
+// NextToken pagination, moves requested reports through _SUBMITTED_, _IN_PROGRESS_ and _DONE_,
+// accepts feeds and produces their processing reports, and throttles operations on demand.
+//
+//	srv := mwstest.NewServer(creds)
+//	defer srv.Close()
+//	srv.AddOrders(mwstest.Order{AmazonOrderID: "111-0000000-0000001", PurchaseDate: time.Now()})
+//	client := srv.Client()
+//	orders, err := amazonmwsapi.NewOrdersAPI(client).ListOrders().CreatedAfter(yesterday).DoAll(ctx)
+package mwstest
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/xml"
+	"fmt"
+	"hash"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	amazonmwsapi "github.com/mike-holberger/amazonmws-go"
+)
+
+// XML namespaces of the emulated API sections
+const (
+	ordersNamespace  = "https://mws.amazonservices.com/Orders/2013-09-01"
+	reportsNamespace = "http://mws.amazonaws.com/doc/2009-01-01/"
+)
+
+// maxTimestampSkew is how far the Timestamp of a request may be off the server clock
+const maxTimestampSkew = 15 * time.Minute
+
+// Server is a fake amzMWS server. Configure its exported fields before sending the first request
+type Server struct {
+	*httptest.Server
+
+	// PageSize is the number of orders, order items and reports per page when the request does not
+	// set MaxResultsPerPage or MaxCount, 100 by default
+	PageSize int
+	// ReportPolls is the number of GetReportRequestList calls a report request spends in each of
+	// _SUBMITTED_ and _IN_PROGRESS_, 1 by default. With 0 report requests are listed as _DONE_ at once
+	ReportPolls int
+	// Clock returns the server time, time.Now by default; set it to simulate clock skew
+	Clock func() time.Time
+
+	mu             sync.Mutex
+	creds          []amazonmwsapi.Creds
+	orders         []Order
+	reportContent  map[string][]byte
+	reportRequests []*reportRequest
+	reports        []*report
+	feeds          []*Feed
+	pages          map[string]*page
+	throttled      map[string]int
+	calls          []string
+	lastID         int
+}
+
+// NewServer starts a fake amzMWS server accepting requests signed with any of creds
+func NewServer(creds ...amazonmwsapi.Creds) *Server {
+	s := &Server{
+		PageSize:      100,
+		ReportPolls:   1,
+		Clock:         time.Now,
+		creds:         creds,
+		reportContent: map[string][]byte{},
+		pages:         map[string]*page{},
+		throttled:     map[string]int{},
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+// Endpoint returns the server URL in the form of amazonmwsapi.Region.Endpoint
+func (s *Server) Endpoint() string {
+	return s.URL + "/"
+}
+
+// Client returns a US AmazonClient for the first credentials of the server, pointed at the server
+// and with rate limiting off. opts are applied after these defaults
+func (s *Server) Client(opts ...amazonmwsapi.Option) *amazonmwsapi.AmazonClient {
+	var creds amazonmwsapi.Creds
+	if len(s.creds) > 0 {
+		creds = s.creds[0]
+	}
+	opts = append([]amazonmwsapi.Option{amazonmwsapi.WithRateLimiter(nil)}, opts...)
+	client := amazonmwsapi.NewAmazonClient(creds, "US", nil, opts...)
+	client.Region.Endpoint = s.Endpoint()
+	return client
+}
+
+// Throttle fails the next n calls of action with a 503 RequestThrottled error. An empty action
+// throttles every operation
+func (s *Server) Throttle(action string, n int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.throttled[action] = n
+}
+
+// Calls returns the Action of every request received, in order, including rejected requests
+func (s *Server) Calls() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.calls...)
+}
+
+// mwsError is an error response of the server
+type mwsError struct {
+	status  int
+	code    string
+	message string
+}
+
+func newError(status int, code, format string, args ...interface{}) *mwsError {
+	return &mwsError{status: status, code: code, message: fmt.Sprintf(format, args...)}
+}
+
+func invalidParameter(name, value string) *mwsError {
+	return newError(http.StatusBadRequest, "InvalidParameterValue", "Invalid %s: %q", name, value)
+}
+
+func missingParameter(name string) *mwsError {
+	return newError(http.StatusBadRequest, "MissingParameter", "The parameter %s is required", name)
+}
+
+// call is a request being served
+type call struct {
+	w         http.ResponseWriter
+	r         *http.Request
+	params    url.Values
+	action    string
+	namespace string
+	requestID string
+	seller    amazonmwsapi.Creds
+}
+
+type handler func(s *Server, c *call) *mwsError
+
+// handlers holds the operations of each API section by endpoint path and Action
+var handlers = map[string]map[string]handler{
+	"/Orders/2013-09-01": {
+		"ListOrders":                (*Server).listOrders,
+		"ListOrdersByNextToken":     (*Server).listOrdersByNextToken,
+		"GetOrder":                  (*Server).getOrder,
+		"ListOrderItems":            (*Server).listOrderItems,
+		"ListOrderItemsByNextToken": (*Server).listOrderItemsByNextToken,
+	},
+	"/Reports/2009-01-01": {
+		"RequestReport":                (*Server).requestReport,
+		"GetReportRequestList":         (*Server).getReportRequestList,
+		"GetReportList":                (*Server).getReportList,
+		"GetReportListByNextToken":     (*Server).getReportListByNextToken,
+		"GetReport":                    (*Server).getReport,
+		"UpdateReportAcknowledgements": (*Server).updateReportAcknowledgements,
+	},
+	"/Feeds/2009-01-01": {
+		"SubmitFeed":              (*Server).submitFeed,
+		"GetFeedSubmissionList":   (*Server).getFeedSubmissionList,
+		"GetFeedSubmissionResult": (*Server).getFeedSubmissionResult,
+	},
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c := &call{
+		w:         w,
+		r:         r,
+		params:    r.URL.Query(),
+		namespace: reportsNamespace,
+		requestID: s.newID("request"),
+	}
+	c.action = c.params.Get("Action")
+	if r.URL.Path == "/Orders/2013-09-01" {
+		c.namespace = ordersNamespace
+	}
+	s.calls = append(s.calls, c.action)
+
+	w.Header().Set("x-mws-request-id", c.requestID)
+	w.Header().Set("x-mws-timestamp", amazonmwsapi.XMLTimestamp(s.Clock()))
+
+	if err := s.serve(c); err != nil {
+		s.writeError(c, err)
+	}
+}
+
+func (s *Server) serve(c *call) *mwsError {
+	operations, ok := handlers[c.r.URL.Path]
+	if !ok {
+		return newError(http.StatusNotFound, "InvalidAddress", "No API section at %s", c.r.URL.Path)
+	}
+	handle, ok := operations[c.action]
+	if !ok {
+		return invalidParameter("Action", c.action)
+	}
+	if err := s.authenticate(c); err != nil {
+		return err
+	}
+	if s.throttle(c.action) {
+		return newError(http.StatusServiceUnavailable, "RequestThrottled", "Request is throttled")
+	}
+	return handle(s, c)
+}
+
+// throttle reports whether a call of action is to be throttled and counts it
+func (s *Server) throttle(action string) bool {
+	for _, key := range []string{action, ""} {
+		if s.throttled[key] > 0 {
+			s.throttled[key]--
+			return true
+		}
+	}
+	return false
+}
+
+// authenticate verifies the Signature Version 2 signature, Timestamp and seller of the call
+func (s *Server) authenticate(c *call) *mwsError {
+	accessID := c.params.Get("AWSAccessKeyId")
+	found := false
+	for _, creds := range s.creds {
+		if creds.AccessID == accessID {
+			c.seller, found = creds, true
+			break
+		}
+	}
+	if !found {
+		return newError(http.StatusUnauthorized, "InvalidAccessKeyId",
+			"The AWS Access Key Id you provided does not exist in our records.")
+	}
+
+	if v := c.params.Get("SignatureVersion"); v != "2" {
+		return invalidParameter("SignatureVersion", v)
+	}
+	var newHash func() hash.Hash
+	switch method := c.params.Get("SignatureMethod"); method {
+	case "HmacSHA256":
+		newHash = sha256.New
+	case "HmacSHA1":
+		newHash = sha1.New
+	default:
+		return invalidParameter("SignatureMethod", method)
+	}
+	signed := url.Values{}
+	for k, v := range c.params {
+		if k != "Signature" {
+			signed[k] = v
+		}
+	}
+	mac := hmac.New(newHash, []byte(c.seller.AccessKey))
+	mac.Write([]byte(amazonmwsapi.StringToSignV2(c.r.Method, c.r.Host, c.r.URL.Path, signed)))
+	want := base64.StdEncoding.EncodeToString(mac.Sum(nil))
+	if !hmac.Equal([]byte(want), []byte(c.params.Get("Signature"))) {
+		return newError(http.StatusForbidden, "SignatureDoesNotMatch",
+			"The request signature we calculated does not match the signature you provided.")
+	}
+
+	timestamp := c.params.Get("Timestamp")
+	if timestamp == "" {
+		return missingParameter("Timestamp")
+	}
+	t, err := time.Parse(time.RFC3339, timestamp)
+	if err != nil {
+		return invalidParameter("Timestamp", timestamp)
+	}
+	if d := t.Sub(s.Clock()); d > maxTimestampSkew || d < -maxTimestampSkew {
+		return newError(http.StatusBadRequest, "RequestExpired", "Request has expired. Timestamp date is %s", timestamp)
+	}
+
+	if seller := c.params.Get("SellerId"); seller != c.seller.Merchant {
+		return newError(http.StatusUnauthorized, "AccessDenied", "Access denied for seller %q", seller)
+	}
+	if c.seller.AuthToken != "" && c.params.Get("MWSAuthToken") != c.seller.AuthToken {
+		return newError(http.StatusUnauthorized, "AccessDenied", "Invalid MWSAuthToken")
+	}
+	return nil
+}
+
+// writeResult writes an <Action>Response holding result as <Action>Result
+func (s *Server) writeResult(c *call, result interface{}) *mwsError {
+	c.w.Header().Set("Content-Type", "text/xml")
+	c.w.WriteHeader(http.StatusOK)
+
+	start := xml.StartElement{
+		Name: xml.Name{Local: c.action + "Response"},
+		Attr: []xml.Attr{{Name: xml.Name{Local: "xmlns"}, Value: c.namespace}},
+	}
+	metadata := struct {
+		RequestID string `xml:"RequestId"`
+	}{c.requestID}
+
+	c.w.Write([]byte(xml.Header))
+	e := xml.NewEncoder(c.w)
+	e.EncodeToken(start)
+	e.EncodeElement(result, xml.StartElement{Name: xml.Name{Local: c.action + "Result"}})
+	e.EncodeElement(metadata, xml.StartElement{Name: xml.Name{Local: "ResponseMetadata"}})
+	e.EncodeToken(start.End())
+	e.Flush()
+	return nil
+}
+
+func (s *Server) writeError(c *call, err *mwsError) {
+	errType := "Sender"
+	if err.status >= 500 && err.code != "RequestThrottled" {
+		errType = "Server"
+	}
+	response := struct {
+		XMLName xml.Name `xml:"ErrorResponse"`
+		Xmlns   string   `xml:"xmlns,attr"`
+		Error   struct {
+			Type    string `xml:"Type"`
+			Code    string `xml:"Code"`
+			Message string `xml:"Message"`
+		} `xml:"Error"`
+		RequestID string `xml:"RequestId"`
+	}{Xmlns: c.namespace, RequestID: c.requestID}
+	response.Error.Type = errType
+	response.Error.Code = err.code
+	response.Error.Message = err.message
+
+	c.w.Header().Set("Content-Type", "text/xml")
+	c.w.WriteHeader(err.status)
+	c.w.Write([]byte(xml.Header))
+	xml.NewEncoder(c.w).Encode(response)
+}
+
+// newID returns a unique identifier with the given prefix
+func (s *Server) newID(prefix string) string {
+	s.lastID++
+	return prefix + "-" + strconv.Itoa(s.lastID)
+}
+
+// pageSize returns the page size requested by param, or the server's PageSize
+func (s *Server) pageSize(c *call, param string, max int) (int, *mwsError) {
+	value := c.params.Get(param)
+	if value == "" {
+		if s.PageSize > 0 && s.PageSize < max {
+			return s.PageSize, nil
+		}
+		return max, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 1 || n > max {
+		return 0, invalidParameter(param, value)
+	}
+	return n, nil
+}
+
+// listParam returns the values of the numbered list param prefix, e.g. OrderStatus.Status.1, .2, ...
+func listParam(params url.Values, prefix string) []string {
+	var values []string
+	for i := 1; ; i++ {
+		v := params.Get(prefix + "." + strconv.Itoa(i))
+		if v == "" {
+			return values
+		}
+		values = append(values, v)
+	}
+}
+
+// timeParam parses the optional ISO8601 param name
+func timeParam(params url.Values, name string) (time.Time, *mwsError) {
+	value := params.Get(name)
+	if value == "" {
+		return time.Time{}, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, invalidParameter(name, value)
+	}
+	return t, nil
+}
+
+func contains(values []string, v string) bool {
+	for _, value := range values {
+		if value == v {
+			return true
+		}
+	}
+	return false
+}
+
+// page holds the remaining results of a paginated list, keyed by NextToken
+type page struct {
+	action  string
+	size    int
+	orderID string
+	orders  []Order
+	items   []OrderItem
+	reports []*report
+}
+
+// nextToken stores the rest of a list and returns its NextToken
+func (s *Server) nextToken(p *page) string {
+	token := base64.StdEncoding.EncodeToString([]byte(s.newID("token")))
+	s.pages[token] = p
+	return token
+}
+
+// takePage removes and returns the page of token, which must belong to the call's Action
+func (s *Server) takePage(c *call) (*page, *mwsError) {
+	token := c.params.Get("NextToken")
+	if token == "" {
+		return nil, missingParameter("NextToken")
+	}
+	p, ok := s.pages[token]
+	if !ok || p.action != c.action {
+		return nil, invalidParameter("NextToken", token)
+	}
+	delete(s.pages, token)
+	return p, nil
+}
@@ -0,0 +1,296 @@
+package mwstest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	amazonmwsapi "github.com/mike-holberger/amazonmws-go"
+)
+
+var testCreds = amazonmwsapi.Creds{AccessID: "AKIATEST", AccessKey: "test-secret", Merchant: "A1TESTSELLER"}
+
+// seedOrders adds n orders purchased a minute apart, starting an hour ago
+func seedOrders(s *Server, n int) time.Time {
+	start := time.Now().Add(-time.Hour).Truncate(time.Second)
+	for i := 0; i < n; i++ {
+		s.AddOrders(Order{
+			AmazonOrderID: fmt.Sprintf("111-0000000-%07d", i),
+			PurchaseDate:  start.Add(time.Duration(i) * time.Minute),
+			Items: []OrderItem{
+				{OrderItemID: "1", SellerSKU: "SKU-1", QuantityOrdered: 1},
+				{OrderItemID: "2", SellerSKU: "SKU-2", QuantityOrdered: 2},
+				{OrderItemID: "3", SellerSKU: "SKU-3", QuantityOrdered: 3},
+			},
+		})
+	}
+	return start
+}
+
+func TestSignature(t *testing.T) {
+	s := NewServer(testCreds)
+	defer s.Close()
+	since := seedOrders(s, 1).Add(-time.Minute)
+	ctx := context.Background()
+
+	if _, err := amazonmwsapi.NewOrdersAPI(s.Client()).ListOrders().CreatedAfter(since).Do(ctx); err != nil {
+		t.Fatal(err)
+	}
+	sha1Client := s.Client(amazonmwsapi.WithSigner(amazonmwsapi.SignatureV2{Method: "HmacSHA1"}))
+	if _, err := amazonmwsapi.NewOrdersAPI(sha1Client).ListOrders().CreatedAfter(since).Do(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		creds amazonmwsapi.Creds
+		code  string
+	}{
+		{"wrong secret", amazonmwsapi.Creds{AccessID: "AKIATEST", AccessKey: "wrong", Merchant: "A1TESTSELLER"}, "SignatureDoesNotMatch"},
+		{"unknown access key", amazonmwsapi.Creds{AccessID: "AKIAOTHER", AccessKey: "test-secret", Merchant: "A1TESTSELLER"}, "InvalidAccessKeyId"},
+		{"other seller", amazonmwsapi.Creds{AccessID: "AKIATEST", AccessKey: "test-secret", Merchant: "A1OTHERSELLER"}, "AccessDenied"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := amazonmwsapi.NewAmazonClient(tt.creds, "US", nil,
+				amazonmwsapi.WithRateLimiter(nil),
+				amazonmwsapi.WithRetryPolicy(amazonmwsapi.NoRetry))
+			client.Region.Endpoint = s.Endpoint()
+			_, err := amazonmwsapi.NewOrdersAPI(client).ListOrders().CreatedAfter(since).Do(ctx)
+			var apiErr *amazonmwsapi.APIError
+			if !errors.As(err, &apiErr) || apiErr.Code != tt.code {
+				t.Fatalf("got %v, want %s", err, tt.code)
+			}
+		})
+	}
+}
+
+func TestListOrdersPaging(t *testing.T) {
+	s := NewServer(testCreds)
+	defer s.Close()
+	s.PageSize = 2
+	start := seedOrders(s, 5)
+
+	orders, err := amazonmwsapi.NewOrdersAPI(s.Client()).ListOrders().
+		CreatedAfter(start.Add(-time.Minute)).
+		DoAll(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ids []string
+	for _, o := range orders {
+		ids = append(ids, o.AmazonOrderID)
+	}
+	want := []string{"111-0000000-0000000", "111-0000000-0000001", "111-0000000-0000002", "111-0000000-0000003", "111-0000000-0000004"}
+	if !reflect.DeepEqual(ids, want) {
+		t.Errorf("got orders %v, want %v", ids, want)
+	}
+	wantCalls := []string{"ListOrders", "ListOrdersByNextToken", "ListOrdersByNextToken"}
+	if calls := s.Calls(); !reflect.DeepEqual(calls, wantCalls) {
+		t.Errorf("got calls %v, want %v", calls, wantCalls)
+	}
+}
+
+func TestListOrderItemsPaging(t *testing.T) {
+	s := NewServer(testCreds)
+	defer s.Close()
+	s.PageSize = 2
+	seedOrders(s, 2)
+
+	items, err := amazonmwsapi.NewOrdersAPI(s.Client()).ListOrderItems("111-0000000-0000001").DoAll(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 3 || items[0].SellerSKU != "SKU-1" || items[2].QuantityOrdered != 3 {
+		t.Errorf("got items %+v", items)
+	}
+}
+
+func TestGetOrder(t *testing.T) {
+	s := NewServer(testCreds)
+	defer s.Close()
+	seedOrders(s, 3)
+
+	resp, err := amazonmwsapi.NewOrdersAPI(s.Client()).
+		GetOrder([]string{"111-0000000-0000000", "111-0000000-0000002"}).
+		Do(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if orders := resp.GetOrderResult.Orders.Order; len(orders) != 2 || orders[1].AmazonOrderID != "111-0000000-0000002" {
+		t.Errorf("got orders %+v", orders)
+	}
+}
+
+func TestInvalidParameter(t *testing.T) {
+	s := NewServer(testCreds)
+	defer s.Close()
+
+	// ListOrders requires CreatedAfter or LastUpdatedAfter
+	_, err := amazonmwsapi.NewOrdersAPI(s.Client()).ListOrders().Do(context.Background())
+	if !amazonmwsapi.IsInvalidParameter(err) {
+		t.Fatalf("got %v, want an invalid parameter error", err)
+	}
+}
+
+func TestReportStates(t *testing.T) {
+	s := NewServer(testCreds)
+	defer s.Close()
+	s.AddReport("_GET_FLAT_FILE_OPEN_LISTINGS_DATA_", []byte("sku\tquantity\nSKU-1\t4\n"))
+	api := amazonmwsapi.NewReportsAPI(s.Client())
+	ctx := context.Background()
+
+	requested, err := api.RequestReport("_GET_FLAT_FILE_OPEN_LISTINGS_DATA_").Do(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	requestID := requested.RequestReportResult.ReportRequestInfo.ReportRequestID
+	if status := requested.RequestReportResult.ReportRequestInfo.ReportProcessingStatus; status != "_SUBMITTED_" {
+		t.Fatalf("got status %s, want _SUBMITTED_", status)
+	}
+
+	var reportID string
+	for _, want := range []string{"_SUBMITTED_", "_IN_PROGRESS_", "_DONE_"} {
+		list, err := api.GetReportRequestList().ReportRequestIDList([]string{requestID}).Do(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		info := list.GetReportRequestListResult.ReportRequestInfo[0]
+		if info.ReportProcessingStatus != want {
+			t.Fatalf("got status %s, want %s", info.ReportProcessingStatus, want)
+		}
+		reportID = info.GeneratedReportID
+	}
+	if reportID == "" {
+		t.Fatal("no GeneratedReportId once _DONE_")
+	}
+
+	rows, err := api.GetReport(reportID).Do(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 1 || rows[0]["sku"] != "SKU-1" || rows[0]["quantity"] != "4" {
+		t.Errorf("got rows %v", rows)
+	}
+
+	unacknowledged, err := api.GetReportList().Acknowledged(false).DoAll(ctx)
+	if err != nil || len(unacknowledged) != 1 || unacknowledged[0].ReportID != reportID {
+		t.Fatalf("got %v %v, want report %s", unacknowledged, err, reportID)
+	}
+	ack, err := api.UpdateReportAcknowledgements([]string{reportID}).Do(ctx)
+	if err != nil || ack.UpdateReportAcknowledgementsResult.Count != "1" {
+		t.Fatalf("got %v %v, want 1 report acknowledged", ack, err)
+	}
+	unacknowledged, err = api.GetReportList().Acknowledged(false).DoAll(ctx)
+	if err != nil || len(unacknowledged) != 0 {
+		t.Fatalf("got %v %v, want no unacknowledged reports", unacknowledged, err)
+	}
+}
+
+func TestThrottle(t *testing.T) {
+	s := NewServer(testCreds)
+	defer s.Close()
+	since := seedOrders(s, 1).Add(-time.Minute)
+	ctx := context.Background()
+
+	s.Throttle("ListOrders", 1)
+	noRetry := amazonmwsapi.NewOrdersAPI(s.Client(amazonmwsapi.WithRetryPolicy(amazonmwsapi.NoRetry)))
+	if _, err := noRetry.ListOrders().CreatedAfter(since).Do(ctx); !amazonmwsapi.IsThrottled(err) {
+		t.Fatalf("got %v, want a throttling error", err)
+	}
+	if _, err := noRetry.ListOrders().CreatedAfter(since).Do(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	s.Throttle("", 2)
+	retry := amazonmwsapi.NewOrdersAPI(s.Client(amazonmwsapi.WithRetryPolicy(
+		amazonmwsapi.ExponentialBackoff{MaxRetries: 2, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond})))
+	if _, err := retry.ListOrders().CreatedAfter(since).Do(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if n := len(s.Calls()); n != 5 {
+		t.Errorf("got %d calls, want 5", n)
+	}
+}
+
+// tamper replaces the body of every response, so it no longer matches its Content-MD5 header
+func tamper(next amazonmwsapi.Handler) amazonmwsapi.Handler {
+	return func(ctx context.Context, call *amazonmwsapi.Call) (*http.Response, error) {
+		resp, err := next(ctx, call)
+		if err == nil {
+			resp.Body.Close()
+			resp.Body = io.NopCloser(strings.NewReader("tampered"))
+		}
+		return resp, err
+	}
+}
+
+func TestReportContentMD5(t *testing.T) {
+	s := NewServer(testCreds)
+	defer s.Close()
+	s.ReportPolls = 0
+	content := []byte("sku\tquantity\nSKU-1\t4\n")
+	s.AddReport("_GET_FLAT_FILE_OPEN_LISTINGS_DATA_", content)
+	client := s.Client()
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	path := filepath.Join(dir, "listings.tsv")
+	if err := amazonmwsapi.NewReportsAPI(client).DownloadInvReport(ctx, "_GET_FLAT_FILE_OPEN_LISTINGS_DATA_", path); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := os.ReadFile(path); string(got) != string(content) {
+		t.Errorf("got report %q, want %q", got, content)
+	}
+
+	reports, err := amazonmwsapi.NewReportsAPI(client).GetReportList().DoAll(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client.Use(tamper)
+	err = amazonmwsapi.NewReportsAPI(client).GetReport(reports[0].ReportID).Download(ctx, filepath.Join(dir, "tampered.tsv"))
+	if !errors.Is(err, amazonmwsapi.ErrChecksumMismatch) {
+		t.Fatalf("got %v, want ErrChecksumMismatch", err)
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 1 {
+		t.Errorf("got %d files, want only the first report", len(entries))
+	}
+}
+
+func TestSubmitFeed(t *testing.T) {
+	s := NewServer(testCreds)
+	defer s.Close()
+	client := s.Client()
+	ctx := context.Background()
+
+	resp, err := amazonmwsapi.NewFeedsAPI(client).SubmitFeed().
+		OrderAcknowledgements([]string{"111-0000000-0000000", "111-0000000-0000001"}).
+		Do(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	feeds := s.Feeds()
+	if len(feeds) != 1 || feeds[0].Error != "" || feeds[0].Messages != 2 || feeds[0].MerchantIdentifier != "A1TESTSELLER" {
+		t.Fatalf("got feeds %+v", feeds)
+	}
+	submissionID := resp.SubmitFeedResult.FeedSubmissionInfo.FeedSubmissionID
+	if submissionID != feeds[0].SubmissionID {
+		t.Errorf("got submission %s, want %s", submissionID, feeds[0].SubmissionID)
+	}
+
+	path := filepath.Join(t.TempDir(), "result.xml")
+	if err := amazonmwsapi.NewFeedsAPI(client).GetFeedSubmissionResult(submissionID).Download(ctx, path); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := os.ReadFile(path); !strings.Contains(string(got), "<ProcessingReport>") {
+		t.Errorf("got processing report %q", got)
+	}
+}
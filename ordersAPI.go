@@ -27,6 +27,7 @@ func (api *OrdersAPI) ListOrders() *ListOrdersRequest {
 			client:   api.client,
 			params:   url.Values{"Action": {"ListOrders"}, "Version": {ordersAPIversion}},
 			endpoint: api.endpoint,
+			section:  ordersSection,
 			method:   "GET",
 		},
 	}
@@ -38,6 +39,7 @@ func (api *OrdersAPI) ListOrderItems(orderID string) *ListOrderItemsRequest {
 		amazonRequest{
 			client:   api.client,
 			endpoint: api.endpoint,
+			section:  ordersSection,
 			params: url.Values{"Action": {"ListOrderItems"}, "Version": {ordersAPIversion},
 				"AmazonOrderId": {orderID}},
 			method: "GET",
@@ -51,7 +53,8 @@ func (api *OrdersAPI) GetOrder(orderIDs []string) *GetOrderRequest {
 		amazonRequest{
 			client: api.client,
 			params: url.Values{"Action": {"GetOrder"}, "Version": {ordersAPIversion}}, endpoint: api.endpoint,
-			method: "GET",
+			section: ordersSection,
+			method:  "GET",
 		},
 	}
 
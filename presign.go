@@ -0,0 +1,49 @@
+package amazonmwsapi
+
+import (
+	"context"
+	"net/http"
+)
+
+// PresignedRequest holds the exact request the library would send to amzMWS, for debugging
+// with Amazon support or replaying it from another tool
+type PresignedRequest struct {
+	Method string
+	URL    string
+	Header http.Header
+	Body   []byte
+}
+
+// Presign signs the request and returns it without sending it. The signature
+// expires with its Timestamp, after 15 minutes. The client's middleware (see Use) is not
+// applied, so params and headers added by middleware are not part of the presigned request
+func (r *amazonRequest) Presign(ctx context.Context) (*PresignedRequest, error) {
+	creds, err := r.client.credentials.Credentials(ctx)
+	if err != nil {
+		return nil, err
+	}
+	request, err := r.client.parseRequest(ctx, r, creds)
+	if err != nil {
+		return nil, err
+	}
+
+	presigned := &PresignedRequest{
+		Method: request.Method,
+		URL:    request.URL.String(),
+		Header: request.Header,
+	}
+	if r.body != nil {
+		presigned.Body = append([]byte(nil), r.body.Bytes()...)
+	}
+	return presigned, nil
+}
+
+// Presign encodes the feed, signs the request and returns it without sending it.
+// As with the other requests, the client's middleware is not applied
+func (r *SubmitFeedRequest) Presign(ctx context.Context) (*PresignedRequest, error) {
+	req, err := r.client.prepareFeed(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+	return req.Presign(ctx)
+}
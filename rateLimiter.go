@@ -0,0 +1,160 @@
+package amazonmwsapi
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Quota holds the MWS throttling parameters of a single operation
+type Quota struct {
+	// MaxRequests is the number of requests that may be sent in a burst
+	MaxRequests int
+	// RestoreRate is the time it takes to restore one request to the quota
+	RestoreRate time.Duration
+}
+
+// DefaultQuotas holds the documented amzMWS quotas of every operation supported by this library
+var DefaultQuotas = map[string]Quota{
+	// Orders API
+	"ListOrders":     {MaxRequests: 6, RestoreRate: time.Minute},
+	"GetOrder":       {MaxRequests: 6, RestoreRate: time.Minute},
+	"ListOrderItems": {MaxRequests: 30, RestoreRate: 2 * time.Second},
+
+	// Reports API
+	"RequestReport":                {MaxRequests: 15, RestoreRate: time.Minute},
+	"GetReportRequestList":         {MaxRequests: 10, RestoreRate: 45 * time.Second},
+	"GetReportList":                {MaxRequests: 10, RestoreRate: time.Minute},
+	"GetReportListByNextToken":     {MaxRequests: 30, RestoreRate: 2 * time.Second},
+	"GetReport":                    {MaxRequests: 15, RestoreRate: time.Minute},
+	"UpdateReportAcknowledgements": {MaxRequests: 10, RestoreRate: 45 * time.Second},
+
+	// Feeds API
+	"SubmitFeed":              {MaxRequests: 15, RestoreRate: 2 * time.Minute},
+	"GetFeedSubmissionResult": {MaxRequests: 15, RestoreRate: time.Minute},
+}
+
+// SharedQuotas maps operations onto the operation whose quota they count against. It applies to
+// operations without a quota of their own
+var SharedQuotas = map[string]string{
+	"ListOrdersByNextToken":     "ListOrders",
+	"ListOrderItemsByNextToken": "ListOrderItems",
+}
+
+// RateLimiter blocks callers until the quota of an operation allows another request.
+// Operations without a quota are not limited. A RateLimiter is safe for concurrent use
+type RateLimiter struct {
+	quotas  map[string]Quota
+	mu      sync.Mutex
+	buckets map[string]*tokenBucket
+	now     func() time.Time
+}
+
+// NewRateLimiter creates a RateLimiter enforcing quotas, keyed by operation (the request Action)
+func NewRateLimiter(quotas map[string]Quota) *RateLimiter {
+	return &RateLimiter{
+		quotas:  quotas,
+		buckets: map[string]*tokenBucket{},
+		now:     time.Now,
+	}
+}
+
+// Wait blocks until operation may be called, or returns the ctx error if ctx is done first
+func (l *RateLimiter) Wait(ctx context.Context, operation string) error {
+	if _, own := l.quotas[operation]; !own && SharedQuotas[operation] != "" {
+		operation = SharedQuotas[operation]
+	}
+
+	l.mu.Lock()
+	bucket, ok := l.buckets[operation]
+	if !ok {
+		quota, limited := l.quotas[operation]
+		if !limited || quota.MaxRequests <= 0 || quota.RestoreRate <= 0 {
+			l.mu.Unlock()
+			return nil
+		}
+		bucket = &tokenBucket{quota: quota, tokens: float64(quota.MaxRequests), last: l.now()}
+		l.buckets[operation] = bucket
+	}
+	l.mu.Unlock()
+
+	delay := bucket.reserve(l.now())
+	if delay <= 0 {
+		return nil
+	}
+	if err := sleepContext(ctx, delay); err != nil {
+		bucket.cancel()
+		return err
+	}
+	return nil
+}
+
+// WithRateLimiter sets the RateLimiter used by the client; nil disables rate limiting.
+// By default clients of the same seller and region share one limiter enforcing DefaultQuotas
+func WithRateLimiter(l *RateLimiter) Option {
+	return func(c *AmazonClient) {
+		c.rateLimiter = l
+		c.rateLimiterSet = true
+	}
+}
+
+var (
+	sellerLimitersMu sync.Mutex
+	sellerLimiters   = map[string]*RateLimiter{}
+)
+
+// limiter returns the RateLimiter set with WithRateLimiter, or else the one of the seller creds
+// are for, looked up on every request as a CredentialsProvider may switch sellers
+func (c *AmazonClient) limiter(creds Creds) *RateLimiter {
+	if c.rateLimiterSet {
+		return c.rateLimiter
+	}
+	return sellerRateLimiter(creds.Merchant, c.Region)
+}
+
+// sellerRateLimiter returns the RateLimiter shared by all clients of a seller in a region
+func sellerRateLimiter(sellerID string, region Region) *RateLimiter {
+	key := sellerID + "/" + region.RegionID
+	sellerLimitersMu.Lock()
+	defer sellerLimitersMu.Unlock()
+	l, ok := sellerLimiters[key]
+	if !ok {
+		l = NewRateLimiter(DefaultQuotas)
+		sellerLimiters[key] = l
+	}
+	return l
+}
+
+// tokenBucket restores one token per quota.RestoreRate up to quota.MaxRequests.
+// Tokens may go negative, representing callers already waiting for a restore
+type tokenBucket struct {
+	mu     sync.Mutex
+	quota  Quota
+	tokens float64
+	last   time.Time
+}
+
+// reserve takes a token and returns how long the caller must wait before using it
+func (b *tokenBucket) reserve(now time.Time) time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.tokens += float64(now.Sub(b.last)) / float64(b.quota.RestoreRate)
+	if burst := float64(b.quota.MaxRequests); b.tokens > burst {
+		b.tokens = burst
+	}
+	b.last = now
+
+	b.tokens--
+	if b.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-b.tokens * float64(b.quota.RestoreRate))
+}
+
+// cancel returns a reserved token that was not used
+func (b *tokenBucket) cancel() {
+	b.mu.Lock()
+	b.tokens++
+	b.mu.Unlock()
+}
@@ -0,0 +1,114 @@
+package amazonmwsapi
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// fakeClock is a settable time source for a RateLimiter
+type fakeClock struct {
+	t time.Time
+}
+
+func (c *fakeClock) now() time.Time          { return c.t }
+func (c *fakeClock) advance(d time.Duration) { c.t = c.t.Add(d) }
+
+func newTestLimiter(quotas map[string]Quota) (*RateLimiter, *fakeClock) {
+	clock := &fakeClock{t: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)}
+	l := NewRateLimiter(quotas)
+	l.now = clock.now
+	return l, clock
+}
+
+func TestTokenBucketReserve(t *testing.T) {
+	quota := Quota{MaxRequests: 2, RestoreRate: time.Minute}
+	tests := []struct {
+		name    string
+		advance time.Duration
+		want    time.Duration
+	}{
+		{"burst 1", 0, 0},
+		{"burst 2", 0, 0},
+		{"burst exhausted", 0, time.Minute},
+		{"queued behind the previous reservation", 0, 2 * time.Minute},
+		{"half restored", 90 * time.Second, time.Minute + 30*time.Second},
+		{"restored after the queue drained", 4 * time.Minute, 0},
+		{"restore is capped at the burst", time.Hour, 0},
+		{"burst after a long idle", 0, 0},
+		{"exhausted again", 0, time.Minute},
+	}
+
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	bucket := &tokenBucket{quota: quota, tokens: float64(quota.MaxRequests), last: now}
+	for _, tt := range tests {
+		now = now.Add(tt.advance)
+		if got := bucket.reserve(now); got != tt.want {
+			t.Errorf("%s: got delay %s, want %s", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestRateLimiterWait(t *testing.T) {
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	tests := []struct {
+		name      string
+		calls     []string
+		ctx       context.Context
+		operation string
+		wantErr   bool
+		// bucket should hold wantTokens after the last Wait
+		bucket     string
+		wantTokens float64
+	}{
+		{"burst", []string{"ListOrders"}, context.Background(), "ListOrders", false, "ListOrders", 4},
+		{"unlimited operation", nil, context.Background(), "GetServiceStatus", false, "GetServiceStatus", 0},
+		{"ByNextToken shares the quota", []string{"ListOrders"}, context.Background(), "ListOrdersByNextToken", false, "ListOrders", 4},
+		{"cancelled wait refunds its token", []string{"ListOrders", "ListOrdersByNextToken", "ListOrders", "ListOrders", "ListOrders", "ListOrders"}, canceled, "ListOrders", true, "ListOrders", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l, _ := newTestLimiter(DefaultQuotas)
+			for _, operation := range tt.calls {
+				if err := l.Wait(context.Background(), operation); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if err := l.Wait(tt.ctx, tt.operation); (err != nil) != tt.wantErr {
+				t.Fatalf("got %v, want error %v", err, tt.wantErr)
+			}
+			if _, ok := l.buckets["ListOrdersByNextToken"]; ok {
+				t.Errorf("ByNextToken operation has a bucket of its own")
+			}
+			bucket := l.buckets[tt.bucket]
+			if bucket == nil {
+				if tt.wantTokens != 0 {
+					t.Fatalf("no bucket for %s", tt.bucket)
+				}
+				return
+			}
+			if bucket.tokens != tt.wantTokens {
+				t.Errorf("got %v tokens left, want %v", bucket.tokens, tt.wantTokens)
+			}
+		})
+	}
+}
+
+func TestRateLimiterRestore(t *testing.T) {
+	l, clock := newTestLimiter(map[string]Quota{"GetOrder": {MaxRequests: 1, RestoreRate: time.Minute}})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if err := l.Wait(ctx, "GetOrder"); err != nil {
+		t.Fatalf("got %v for the burst, want no wait", err)
+	}
+	if err := l.Wait(ctx, "GetOrder"); err == nil {
+		t.Fatal("exhausted quota did not wait")
+	}
+	clock.advance(time.Minute)
+	if err := l.Wait(ctx, "GetOrder"); err != nil {
+		t.Fatalf("got %v once a request was restored, want no wait", err)
+	}
+}
@@ -0,0 +1,101 @@
+package amazonmwsapi
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+)
+
+// RedactionLevel controls how much sensitive data is masked in log output
+type RedactionLevel int
+
+const (
+	// RedactAll masks credentials, signatures and buyer PII; this is the default
+	RedactAll RedactionLevel = iota
+	// RedactCredentials masks credentials and signatures but logs response bodies verbatim
+	RedactCredentials
+	// RedactNone logs requests and responses verbatim, for local debugging only
+	RedactNone
+)
+
+const redacted = "REDACTED"
+
+// credentialParams are request params masked at RedactCredentials and above
+var credentialParams = []string{"AWSAccessKeyId", "SellerId", "Signature", "MWSAuthToken"}
+
+// credentialElements and piiElements are XML elements masked in logged bodies
+var (
+	credentialElements = regexp.MustCompile(`<(MerchantIdentifier|SellerId)>[^<]*</`)
+	piiElements        = regexp.MustCompile(`<(BuyerEmail|BuyerName|Name|AddressLine1|AddressLine2|AddressLine3|` +
+		`City|County|District|StateOrRegion|PostalCode|Phone|CompanyLegalName|BuyerCounty)>[^<]*</`)
+)
+
+// WithRedaction sets how much sensitive data is masked in log output, see RedactionLevel
+func WithRedaction(level RedactionLevel) Option {
+	return func(c *AmazonClient) {
+		c.redaction = level
+	}
+}
+
+// RedactParams returns a copy of params with credentials and signatures masked
+func RedactParams(params url.Values, level RedactionLevel) url.Values {
+	masked := copyValues(params)
+	if level >= RedactNone {
+		return masked
+	}
+	for _, key := range credentialParams {
+		if _, ok := masked[key]; ok {
+			masked.Set(key, redacted)
+		}
+	}
+	return masked
+}
+
+// RedactXML masks credential elements and, at RedactAll, buyer PII elements in an amzMWS XML body
+func RedactXML(body []byte, level RedactionLevel) []byte {
+	if level >= RedactNone {
+		return body
+	}
+	body = credentialElements.ReplaceAll(body, []byte("<$1>"+redacted+"</"))
+	if level == RedactAll {
+		body = piiElements.ReplaceAll(body, []byte("<$1>"+redacted+"</"))
+	}
+	return body
+}
+
+// redactURL returns u as a string with credentials and signatures masked
+func redactURL(u *url.URL, level RedactionLevel) string {
+	if u == nil {
+		return ""
+	}
+	if level >= RedactNone {
+		return u.String()
+	}
+
+	masked := *u
+	masked.RawQuery = CanonicalizedQueryString(RedactParams(u.Query(), level))
+	return masked.String()
+}
+
+// redactBody returns a response body prepared for logging. At RedactAll non-XML bodies such as
+// TSV reports are not logged at all, as they cannot be reliably scrubbed of buyer data
+func redactBody(body []byte, level RedactionLevel) string {
+	if level == RedactAll && !bytes.HasPrefix(bytes.TrimSpace(body), []byte("<")) {
+		return fmt.Sprintf("[%d bytes %s]", len(body), redacted)
+	}
+	return string(RedactXML(body, level))
+}
+
+// redactError masks the signed URL embedded in transport errors
+func redactError(err error, level RedactionLevel) error {
+	var urlErr *url.Error
+	if level >= RedactNone || !errors.As(err, &urlErr) {
+		return err
+	}
+	if u, perr := url.Parse(urlErr.URL); perr == nil {
+		urlErr.URL = redactURL(u, level)
+	}
+	return err
+}
@@ -0,0 +1,85 @@
+package amazonmwsapi
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Region holds data about the target marketplace website
+type Region struct {
+	RegionID      string
+	Country       string
+	Endpoint      string
+	MarketPlaceID string
+	Currency      string
+	Language      string
+	TimeZone      string
+	// EndpointGroup names the marketplaces a single seller account can address together: a
+	// request to one of them may list any marketplace of the same group
+	EndpointGroup string
+}
+
+// Location returns the time zone of the marketplace
+func (r Region) Location() (*time.Location, error) {
+	return time.LoadLocation(r.TimeZone)
+}
+
+// countryAliases maps ISO 3166 country codes to the codes used in Regions
+var countryAliases = map[string]string{"GB": "UK"}
+
+// RegionByCountry returns Region data for a specified country
+func RegionByCountry(country string) (Region, error) {
+	if alias, ok := countryAliases[strings.ToUpper(country)]; ok {
+		country = alias
+	}
+	for _, region := range Regions {
+		if strings.EqualFold(region.Country, country) {
+			return region, nil
+		}
+	}
+	return Region{}, fmt.Errorf("unknown marketplace country %q", country)
+}
+
+// RegionByMarketplaceID returns Region data for a marketplace ID
+func RegionByMarketplaceID(marketplaceID string) (Region, error) {
+	for _, region := range Regions {
+		if region.MarketPlaceID == marketplaceID {
+			return region, nil
+		}
+	}
+	return Region{}, fmt.Errorf("unknown marketplace ID %q", marketplaceID)
+}
+
+// Regions is holding pre-loaded marketplace data:
+// region, country, endpoint, marketplace ID, default currency, default language, time zone, endpoint group
+var Regions = []Region{
+	// North America
+	{"NA", "US", "https://mws.amazonservices.com/", "ATVPDKIKX0DER", "USD", "en_US", "America/Los_Angeles", "NA"},
+	{"NA", "CA", "https://mws.amazonservices.ca/", "A2EUQ1WTGCTBG2", "CAD", "en_CA", "America/Toronto", "NA"},
+	{"NA", "MX", "https://mws.amazonservices.com.mx/", "A1AM78C64UM0Y8", "MXN", "es_MX", "America/Mexico_City", "NA"},
+	{"NA", "BR", "https://mws.amazonservices.com/", "A2Q3Y263D00KWC", "BRL", "pt_BR", "America/Sao_Paulo", "NA"},
+
+	// Europe, Middle East and India
+	{"EU", "UK", "https://mws-eu.amazonservices.com/", "A1F83G8C2ARO7P", "GBP", "en_GB", "Europe/London", "EU"},
+	{"EU", "DE", "https://mws-eu.amazonservices.com/", "A1PA6795UKMFR9", "EUR", "de_DE", "Europe/Berlin", "EU"},
+	{"EU", "ES", "https://mws-eu.amazonservices.com/", "A1RKKUPIHCS9HS", "EUR", "es_ES", "Europe/Madrid", "EU"},
+	{"EU", "FR", "https://mws-eu.amazonservices.com/", "A13V1IB3VIYZZH", "EUR", "fr_FR", "Europe/Paris", "EU"},
+	{"EU", "IT", "https://mws-eu.amazonservices.com/", "APJ6JRA9NG5V4", "EUR", "it_IT", "Europe/Rome", "EU"},
+	{"EU", "NL", "https://mws-eu.amazonservices.com/", "A1805IZSGTT6HS", "EUR", "nl_NL", "Europe/Amsterdam", "EU"},
+	{"EU", "SE", "https://mws-eu.amazonservices.com/", "A2NODRKZP88ZB9", "SEK", "sv_SE", "Europe/Stockholm", "EU"},
+	{"EU", "PL", "https://mws-eu.amazonservices.com/", "A1C3SOZRARQ6R3", "PLN", "pl_PL", "Europe/Warsaw", "EU"},
+	{"EU", "TR", "https://mws-eu.amazonservices.com/", "A33AVAJ2PDY3EV", "TRY", "tr_TR", "Europe/Istanbul", "EU"},
+	{"EU", "SA", "https://mws-eu.amazonservices.com/", "A17E79C6D8DWNP", "SAR", "ar_SA", "Asia/Riyadh", "EU"},
+	{"EU", "EG", "https://mws-eu.amazonservices.com/", "ARBP9OOSHTCHU", "EGP", "ar_EG", "Africa/Cairo", "EU"},
+	{"EU", "AE", "https://mws.amazonservices.ae/", "A2VIGQ35RCS4UG", "AED", "ar_AE", "Asia/Dubai", "AE"},
+	{"EU", "IN", "https://mws.amazonservices.in/", "A21TJRUUN4KGV", "INR", "en_IN", "Asia/Kolkata", "IN"},
+
+	// Far East
+	{"FE", "JP", "https://mws.amazonservices.jp/", "A1VC38T7YXB528", "JPY", "ja_JP", "Asia/Tokyo", "JP"},
+	{"FE", "AU", "https://mws.amazonservices.com.au/", "A39IBJ37TRP1C6", "AUD", "en_AU", "Australia/Sydney", "AU"},
+	{"FE", "SG", "https://mws-fe.amazonservices.com/", "A19VAU5U5O7RUS", "SGD", "en_SG", "Asia/Singapore", "SG"},
+
+	// China
+	{"CN", "CN", "https://mws.amazonservices.com.cn/", "AAHKV2X7AFYLW", "CNY", "zh_CN", "Asia/Shanghai", "CN"},
+}
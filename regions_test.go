@@ -0,0 +1,39 @@
+package amazonmwsapi
+
+import "testing"
+
+func TestValidateMarketplaces(t *testing.T) {
+	tests := []struct {
+		country      string
+		marketplaces []string
+		valid        bool
+	}{
+		{"US", []string{"ATVPDKIKX0DER", "A2EUQ1WTGCTBG2", "A1AM78C64UM0Y8"}, true}, // US, CA, MX
+		{"CA", []string{"ATVPDKIKX0DER"}, true},
+		{"UK", []string{"A1PA6795UKMFR9", "A13V1IB3VIYZZH"}, true}, // DE, FR
+		{"US", []string{"A1PA6795UKMFR9"}, false},
+		{"UK", []string{"A21TJRUUN4KGV"}, false}, // IN has its own endpoint
+		{"JP", []string{"A39IBJ37TRP1C6"}, false},
+		{"US", []string{"unknown"}, false},
+	}
+	for _, tt := range tests {
+		c := NewAmazonClient(Creds{}, tt.country, nil, WithRateLimiter(nil))
+		c.Region.Endpoint = "http://127.0.0.1/"
+		if err := c.validateMarketplaces(tt.marketplaces); (err == nil) != tt.valid {
+			t.Errorf("%s %v: got %v, want valid %v", tt.country, tt.marketplaces, err, tt.valid)
+		}
+	}
+}
+
+func TestRegionEndpoints(t *testing.T) {
+	for country, want := range map[string]string{
+		"US": "https://mws.amazonservices.com/",
+		"CA": "https://mws.amazonservices.ca/",
+		"MX": "https://mws.amazonservices.com.mx/",
+		"GB": "https://mws-eu.amazonservices.com/",
+	} {
+		if region, err := RegionByCountry(country); err != nil || region.Endpoint != want {
+			t.Errorf("%s: got %q %v, want %q", country, region.Endpoint, err, want)
+		}
+	}
+}
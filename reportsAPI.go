@@ -28,6 +28,7 @@ func (api *ReportsAPI) GetReportList() *GetReportListRequest {
 		amazonRequest{
 			client:   api.client,
 			endpoint: api.endpoint,
+			section:  reportsSection,
 			params:   url.Values{"Action": {"GetReportList"}, "Version": {reportsAPIversion}},
 			method:   "POST",
 		},
@@ -40,6 +41,7 @@ func (api *ReportsAPI) GetReport(reportID string) *GetReportRequest {
 		amazonRequest{
 			client:   api.client,
 			endpoint: api.endpoint,
+			section:  reportsSection,
 			params: url.Values{"Action": {"GetReport"}, "Version": {reportsAPIversion},
 				"ReportId": {reportID}},
 			method: "POST",
@@ -53,12 +55,13 @@ func (api *ReportsAPI) UpdateReportAcknowledgements(reportIDs []string) *UpdateR
 		amazonRequest{
 			client:   api.client,
 			endpoint: api.endpoint,
+			section:  reportsSection,
 			params:   url.Values{"Action": {"UpdateReportAcknowledgements"}, "Version": {reportsAPIversion}},
 			method:   "POST",
 		},
 	}
 	for i, rep := range reportIDs {
-		key := fmt.Sprintf("ReportIdList.Type.%d", (i + 1))
+		key := fmt.Sprintf("ReportIdList.Id.%d", (i + 1))
 		req.params.Add(key, rep)
 	}
 	return req
@@ -70,6 +73,7 @@ func (api *ReportsAPI) RequestReport(reportType string) *RequestReportRequest {
 		amazonRequest{
 			client:   api.client,
 			endpoint: api.endpoint,
+			section:  reportsSection,
 			params: url.Values{"Action": {"RequestReport"}, "Version": {reportsAPIversion},
 				"ReportType": {reportType}},
 			method: "POST",
@@ -83,6 +87,7 @@ func (api *ReportsAPI) GetReportRequestList() *GetReportRequestListRequest {
 		amazonRequest{
 			client:   api.client,
 			endpoint: api.endpoint,
+			section:  reportsSection,
 			params:   url.Values{"Action": {"GetReportRequestList"}, "Version": {reportsAPIversion}},
 			method:   "POST",
 		},
@@ -112,7 +117,9 @@ func (api *ReportsAPI) DownloadInvReport(ctx context.Context, reportType string,
 		if len(repReqListResp.GetReportRequestListResult.ReportRequestInfo) == 0 ||
 			repReqListResp.GetReportRequestListResult.ReportRequestInfo[0].ReportProcessingStatus != "_DONE_" {
 			// Report not ready, try again later (strict request limit here => 15 calls)
-			time.Sleep(4 * time.Second)
+			if err := sleepContext(ctx, 4*time.Second); err != nil {
+				return err
+			}
 			continue
 		}
 
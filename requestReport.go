@@ -10,18 +10,26 @@ type RequestReportRequest struct {
 	amazonRequest
 }
 
+// Clone returns a copy of the request that can be modified and executed independently
+func (r *RequestReportRequest) Clone() *RequestReportRequest {
+	return &RequestReportRequest{r.amazonRequest.clone()}
+}
+
+// Marketplaces selects the marketplaces of the request, which must all share the client's endpoint.
+// By default the client's own marketplace is used
+func (r *RequestReportRequest) Marketplaces(marketplaceIDs ...string) *RequestReportRequest {
+	r.marketplaces = marketplaceIDs
+	return r
+}
+
 // Do sends request to amazonMWS reports API and returns report request info
 func (r *RequestReportRequest) Do(ctx context.Context) (*RequestReportResponse, error) {
-	respBytes, err := r.client.callAPI(ctx, &r.amazonRequest)
-	if err != nil {
-		return nil, err
-	}
-
 	xmlResponse := &RequestReportResponse{}
-	err = xml.Unmarshal(respBytes, xmlResponse)
+	header, err := r.client.callAPI(ctx, &r.amazonRequest, xmlResponse)
 	if err != nil {
-		return nil, r.client.parseAPIerrors(respBytes)
+		return nil, err
 	}
+	xmlResponse.ResponseMetadata.setHeader(header)
 
 	return xmlResponse, nil
 }
@@ -41,7 +49,5 @@ type RequestReportResponse struct {
 			StartDate              string `xml:"StartDate"`
 		} `xml:"ReportRequestInfo"`
 	} `xml:"RequestReportResult"`
-	ResponseMetadata struct {
-		RequestID string `xml:"RequestId"`
-	} `xml:"ResponseMetadata"`
+	ResponseMetadata ResponseMetadata `xml:"ResponseMetadata"`
 }
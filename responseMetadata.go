@@ -0,0 +1,44 @@
+package amazonmwsapi
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// ResponseMetadata holds the request ID and the quota data returned in amzMWS response headers.
+// Quota fields are only set for operations whose responses include the x-mws-quota-* headers
+type ResponseMetadata struct {
+	RequestID      string    `xml:"RequestId"`
+	QuotaMax       float64   `xml:"-"`
+	QuotaRemaining float64   `xml:"-"`
+	QuotaResetsOn  time.Time `xml:"-"`
+	Timestamp      time.Time `xml:"-"`
+}
+
+// HasQuota reports whether the response carried quota headers
+func (m *ResponseMetadata) HasQuota() bool {
+	return !m.QuotaResetsOn.IsZero()
+}
+
+// setHeader parses the x-mws-* response headers into m
+func (m *ResponseMetadata) setHeader(header http.Header) {
+	if header == nil {
+		return
+	}
+	if id := header.Get("x-mws-request-id"); id != "" && m.RequestID == "" {
+		m.RequestID = id
+	}
+	if v, err := strconv.ParseFloat(header.Get("x-mws-quota-max"), 64); err == nil {
+		m.QuotaMax = v
+	}
+	if v, err := strconv.ParseFloat(header.Get("x-mws-quota-remaining"), 64); err == nil {
+		m.QuotaRemaining = v
+	}
+	if t, err := time.Parse(time.RFC3339, header.Get("x-mws-quota-resetsOn")); err == nil {
+		m.QuotaResetsOn = t
+	}
+	if t, err := time.Parse(time.RFC3339, header.Get("x-mws-timestamp")); err == nil {
+		m.Timestamp = t
+	}
+}
@@ -0,0 +1,141 @@
+package amazonmwsapi
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+)
+
+// RetryPolicy decides whether and when a failed amzMWS call is attempted again
+type RetryPolicy interface {
+	// Backoff returns the delay before retry number attempt (starting at 1) of operation,
+	// or false if err should be returned to the caller
+	Backoff(operation string, attempt int, err error) (time.Duration, bool)
+}
+
+// DefaultRetryPolicy retries throttled, server and transport errors up to 3 times.
+// Non-idempotent operations (see IsIdempotent) are only retried when throttled
+var DefaultRetryPolicy RetryPolicy = ExponentialBackoff{
+	MaxRetries: 3,
+	BaseDelay:  time.Second,
+	MaxDelay:   30 * time.Second,
+}
+
+// NoRetry returns every error to the caller immediately
+var NoRetry RetryPolicy = ExponentialBackoff{}
+
+// ExponentialBackoff retries retryable errors (see IsRetryable), doubling the delay on each attempt.
+// The delay is randomized between half and all of its nominal value to spread out concurrent callers
+type ExponentialBackoff struct {
+	MaxRetries int
+	BaseDelay  time.Duration
+	MaxDelay   time.Duration
+	// RetryNonIdempotent opts non-idempotent operations, e.g. "SubmitFeed", in to retrying server
+	// and transport errors. A retry may then repeat a request amzMWS already accepted
+	RetryNonIdempotent map[string]bool
+}
+
+// Backoff implements RetryPolicy
+func (b ExponentialBackoff) Backoff(operation string, attempt int, err error) (time.Duration, bool) {
+	if attempt > b.MaxRetries || !IsRetryable(err) {
+		return 0, false
+	}
+	if !IsIdempotent(operation) && !b.RetryNonIdempotent[operation] && !IsThrottled(err) {
+		return 0, false
+	}
+
+	delay := b.BaseDelay
+	for i := 1; i < attempt && (b.MaxDelay <= 0 || delay < b.MaxDelay); i++ {
+		delay *= 2
+	}
+	if b.MaxDelay > 0 && delay > b.MaxDelay {
+		delay = b.MaxDelay
+	}
+	if delay <= 0 {
+		return 0, true
+	}
+
+	half := delay / 2
+	return half + time.Duration(rand.Int63n(int64(delay-half)+1)), true
+}
+
+// OperationRetryPolicy selects a RetryPolicy by operation (the request Action), falling back to Default
+type OperationRetryPolicy struct {
+	Default    RetryPolicy
+	Operations map[string]RetryPolicy
+}
+
+// Backoff implements RetryPolicy
+func (p OperationRetryPolicy) Backoff(operation string, attempt int, err error) (time.Duration, bool) {
+	if policy, ok := p.Operations[operation]; ok {
+		return policy.Backoff(operation, attempt, err)
+	}
+	if p.Default == nil {
+		return 0, false
+	}
+	return p.Default.Backoff(operation, attempt, err)
+}
+
+// WithRetryPolicy sets the RetryPolicy used for every call, NoRetry disables retries
+func WithRetryPolicy(p RetryPolicy) Option {
+	return func(c *AmazonClient) {
+		c.retryPolicy = p
+	}
+}
+
+// IsRetryable reports whether err is a throttling, server or transient transport error worth retrying
+func IsRetryable(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return IsThrottled(apiErr) ||
+			apiErr.StatusCode == http.StatusInternalServerError ||
+			apiErr.StatusCode == http.StatusServiceUnavailable
+	}
+	return isTransient(err)
+}
+
+// isTransient reports whether err is a transport error that may not recur: a timeout, a reset or
+// refused connection, or a connection closed mid-response. Every error of http.Client.Do is a
+// net.Error (*url.Error), so TLS, proxy and URL errors must not be told apart by type alone
+func isTransient(err error) bool {
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+	return errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+// nonIdempotentOperations create a feed submission or report request every time they are sent
+var nonIdempotentOperations = map[string]bool{
+	"SubmitFeed":    true,
+	"RequestReport": true,
+}
+
+// IsIdempotent reports whether operation can safely be sent again after a server or transport
+// error, when amzMWS may already have accepted the first request
+func IsIdempotent(operation string) bool {
+	return !nonIdempotentOperations[operation]
+}
+
+// sleepContext waits for d or until ctx is done
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,126 @@
+package amazonmwsapi
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// timeoutError is a net.Error that timed out
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+// transportError wraps err the way http.Client.Do does
+func transportError(err error) error {
+	return &url.Error{Op: "Post", URL: "https://mws.amazonservices.com/Orders/2013-09-01", Err: err}
+}
+
+func TestIsRetryable(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"nil", nil, false},
+		{"throttled", &APIError{StatusCode: 503, Code: "RequestThrottled"}, true},
+		{"quota exceeded", &APIError{StatusCode: 503, Code: "QuotaExceeded"}, true},
+		{"internal error", &APIError{StatusCode: 500, Code: "InternalError"}, true},
+		{"service unavailable", &APIError{StatusCode: 503, Code: "ServiceUnavailable"}, true},
+		{"invalid parameter", &APIError{StatusCode: 400, Code: "InvalidParameterValue"}, false},
+		{"access denied", &APIError{StatusCode: 401, Code: "AccessDenied"}, false},
+		{"timeout", transportError(timeoutError{}), true},
+		{"connection reset", transportError(&net.OpError{Op: "read", Err: os.NewSyscallError("read", syscall.ECONNRESET)}), true},
+		{"connection refused", transportError(&net.OpError{Op: "dial", Err: os.NewSyscallError("connect", syscall.ECONNREFUSED)}), true},
+		{"unexpected EOF", transportError(io.ErrUnexpectedEOF), true},
+		{"x509", transportError(x509.UnknownAuthorityError{}), false},
+		{"no such host", transportError(&net.DNSError{Err: "no such host", Name: "mws.example", IsNotFound: true}), false},
+		{"bad proxy", transportError(errors.New("proxyconnect tcp: dial tcp: lookup proxy: no such host")), false},
+		{"canceled", transportError(context.Canceled), false},
+		{"deadline exceeded", fmt.Errorf("ListOrders: %w", context.DeadlineExceeded), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsRetryable(tt.err); got != tt.want {
+				t.Errorf("IsRetryable(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExponentialBackoff(t *testing.T) {
+	b := ExponentialBackoff{MaxRetries: 3, BaseDelay: time.Second, MaxDelay: 3 * time.Second}
+	throttled := &APIError{StatusCode: 503, Code: "RequestThrottled"}
+	for attempt, nominal := range map[int]time.Duration{1: time.Second, 2: 2 * time.Second, 3: 3 * time.Second} {
+		delay, ok := b.Backoff("ListOrders", attempt, throttled)
+		if !ok || delay < nominal/2 || delay > nominal {
+			t.Errorf("attempt %d: got %v %v, want between %v and %v", attempt, delay, ok, nominal/2, nominal)
+		}
+	}
+	if _, ok := b.Backoff("ListOrders", 4, throttled); ok {
+		t.Error("retried after MaxRetries")
+	}
+}
+
+func TestNonIdempotentRetry(t *testing.T) {
+	serverErr := &APIError{StatusCode: 500, Code: "InternalError"}
+	throttled := &APIError{StatusCode: 503, Code: "RequestThrottled"}
+	reset := transportError(os.NewSyscallError("read", syscall.ECONNRESET))
+	b := ExponentialBackoff{MaxRetries: 1}
+
+	tests := []struct {
+		operation string
+		err       error
+		want      bool
+	}{
+		{"ListOrders", serverErr, true},
+		{"SubmitFeed", serverErr, false},
+		{"SubmitFeed", reset, false},
+		{"RequestReport", serverErr, false},
+		{"SubmitFeed", throttled, true},
+		{"RequestReport", throttled, true},
+	}
+	for _, tt := range tests {
+		if _, got := b.Backoff(tt.operation, 1, tt.err); got != tt.want {
+			t.Errorf("%s %v: retried %v, want %v", tt.operation, tt.err, got, tt.want)
+		}
+	}
+
+	b.RetryNonIdempotent = map[string]bool{"SubmitFeed": true}
+	if _, ok := b.Backoff("SubmitFeed", 1, serverErr); !ok {
+		t.Error("SubmitFeed opted in but not retried")
+	}
+}
+
+func TestTLSErrorNotRetried(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+	c := NewAmazonClient(Creds{AccessID: "a", AccessKey: "k", Merchant: "m"}, "US", nil,
+		WithRateLimiter(nil),
+		WithRetryPolicy(ExponentialBackoff{MaxRetries: 3}))
+	c.Region.Endpoint = srv.URL + "/"
+	attempts := 0
+	c.Use(func(next Handler) Handler {
+		return func(ctx context.Context, call *Call) (*http.Response, error) {
+			attempts++
+			return next(ctx, call)
+		}
+	})
+
+	_, err := NewOrdersAPI(c).GetOrder([]string{"1"}).Do(context.Background())
+	if err == nil || attempts != 1 {
+		t.Fatalf("got %v after %d attempts, want a certificate error after 1", err, attempts)
+	}
+}
@@ -0,0 +1,195 @@
+package amazonmwsapi
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Signer authenticates a request to amzMWS. When Sign is called the request URL holds all
+// request params, including AWSAccessKeyId and Timestamp; body is the request body, if any
+type Signer interface {
+	Sign(req *http.Request, body []byte, creds Creds, t time.Time) error
+}
+
+// WithSigner sets the Signer used for every request, SignatureV2 with HmacSHA256 by default
+func WithSigner(s Signer) Option {
+	return func(c *AmazonClient) {
+		c.Signer = s
+	}
+}
+
+// SignatureV2 implements the amzMWS Signature Version 2 scheme, which adds the
+// SignatureMethod, SignatureVersion and Signature params to the query string
+type SignatureV2 struct {
+	// Method is "HmacSHA256" (the default when empty) or "HmacSHA1"
+	Method string
+}
+
+// Sign implements Signer
+func (s SignatureV2) Sign(req *http.Request, body []byte, creds Creds, t time.Time) error {
+	method := s.method()
+	var newHash func() hash.Hash
+	switch method {
+	case "HmacSHA256":
+		newHash = sha256.New
+	case "HmacSHA1":
+		newHash = sha1.New
+	default:
+		return fmt.Errorf("unsupported SignatureMethod %q", method)
+	}
+
+	params := req.URL.Query()
+	params.Del("Signature")
+	params.Set("SignatureMethod", method)
+	params.Set("SignatureVersion", "2")
+
+	mac := hmac.New(newHash, []byte(creds.AccessKey))
+	mac.Write([]byte(StringToSignV2(req.Method, req.URL.Host, req.URL.Path, params)))
+	params.Set("Signature", base64.StdEncoding.EncodeToString(mac.Sum(nil)))
+
+	req.URL.RawQuery = CanonicalizedQueryString(params)
+	return nil
+}
+
+func (s SignatureV2) method() string {
+	if s.Method == "" {
+		return "HmacSHA256"
+	}
+	return s.Method
+}
+
+// signer returns the client's Signer, applying changes made to the deprecated SignatureMethod
+// and SignatureVersion fields. SignatureMethod only overrides the Signer when the caller changed
+// it after NewAmazonClient, so a SignatureV2 Signer set later on is used as is
+func (c *AmazonClient) signer() (Signer, error) {
+	if c.SignatureVersion != "" && c.SignatureVersion != "2" {
+		return nil, fmt.Errorf("unsupported SignatureVersion %q, set Signer instead", c.SignatureVersion)
+	}
+	if v2, ok := c.Signer.(SignatureV2); ok && c.SignatureMethod != c.signatureMethod && c.SignatureMethod != "" && c.SignatureMethod != v2.method() {
+		return SignatureV2{Method: c.SignatureMethod}, nil
+	}
+	return c.Signer, nil
+}
+
+// StringToSignV2 returns the Signature Version 2 string to sign of a request
+func StringToSignV2(method, host, path string, params url.Values) string {
+	if path == "" {
+		path = "/"
+	}
+	return strings.Join([]string{
+		method,
+		strings.ToLower(host),
+		path,
+		CanonicalizedQueryString(params),
+	}, "\n")
+}
+
+// SignatureV4 implements the AWS Signature Version 4 scheme used by newer Amazon endpoints,
+// which sends the signature in the Authorization header
+type SignatureV4 struct {
+	// Region is the AWS region of the endpoint, e.g. "us-east-1"
+	Region string
+	// Service is the AWS service name, e.g. "execute-api"
+	Service string
+}
+
+const (
+	sigV4Algorithm  = "AWS4-HMAC-SHA256"
+	sigV4TimeFormat = "20060102T150405Z"
+)
+
+// Sign implements Signer
+func (s SignatureV4) Sign(req *http.Request, body []byte, creds Creds, t time.Time) error {
+	amzDate := t.UTC().Format(sigV4TimeFormat)
+	scope := strings.Join([]string{amzDate[:8], s.Region, s.Service, "aws4_request"}, "/")
+	req.Header.Set("X-Amz-Date", amzDate)
+
+	// Sign the host and all x-amz-* headers
+	headers := map[string]string{"host": strings.ToLower(req.URL.Host)}
+	for name, values := range req.Header {
+		if name = strings.ToLower(name); strings.HasPrefix(name, "x-amz-") {
+			headers[name] = strings.Join(values, ",")
+		}
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	canonicalHeaders := ""
+	for _, name := range names {
+		canonicalHeaders += name + ":" + strings.TrimSpace(headers[name]) + "\n"
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	path := req.URL.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+	payloadHash := sha256.Sum256(body)
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		path,
+		canonicalQueryStringV4(req.URL.Query()),
+		canonicalHeaders,
+		signedHeaders,
+		hex.EncodeToString(payloadHash[:]),
+	}, "\n")
+
+	requestHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := strings.Join([]string{sigV4Algorithm, amzDate, scope, hex.EncodeToString(requestHash[:])}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+creds.AccessKey), amzDate[:8])
+	key = hmacSHA256(key, s.Region)
+	key = hmacSHA256(key, s.Service)
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		sigV4Algorithm, creds.AccessID, scope, signedHeaders, signature))
+	return nil
+}
+
+// canonicalQueryStringV4 sorts params by encoded name and then value, as Signature Version 4 requires
+func canonicalQueryStringV4(params url.Values) string {
+	type pair struct{ name, value string }
+	pairs := make([]pair, 0, len(params))
+	for name, values := range params {
+		for _, value := range values {
+			pairs = append(pairs, pair{uriEncode(name), uriEncode(value)})
+		}
+	}
+	sort.Slice(pairs, func(i, j int) bool {
+		if pairs[i].name != pairs[j].name {
+			return pairs[i].name < pairs[j].name
+		}
+		return pairs[i].value < pairs[j].value
+	})
+
+	encoded := make([]string, len(pairs))
+	for i, p := range pairs {
+		encoded[i] = p.name + "=" + p.value
+	}
+	return strings.Join(encoded, "&")
+}
+
+// uriEncode percent-encodes all but the RFC 3986 unreserved characters
+func uriEncode(s string) string {
+	return strings.Replace(url.QueryEscape(s), "+", "%20", -1)
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
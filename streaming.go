@@ -0,0 +1,171 @@
+package amazonmwsapi
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"net/http"
+)
+
+// DefaultMaxResponseSize is the default limit on the size of XML responses, see WithMaxResponseSize
+const DefaultMaxResponseSize = 64 << 20
+
+// maxLoggedBody is the most of a response body included in the debug log
+const maxLoggedBody = 64 << 10
+
+// ErrResponseTooLarge is returned for XML responses larger than the client's maximum response size
+var ErrResponseTooLarge = errors.New("amzMWS response exceeds the maximum response size")
+
+// WithMaxResponseSize limits the size of the XML responses the client decodes, DefaultMaxResponseSize
+// by default; 0 or less removes the limit. Reports read with Stream or Download are not limited
+func WithMaxResponseSize(n int64) Option {
+	return func(c *AmazonClient) {
+		c.maxResponseSize = n
+	}
+}
+
+// decodeXML decodes the XML document of r into v, or into an *APIError when it is an ErrorResponse
+func decodeXML(r io.Reader, operation string, v interface{}) error {
+	decoder := xml.NewDecoder(r)
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return decodeError(operation, err)
+		}
+		start, ok := token.(xml.StartElement)
+		if !ok {
+			continue
+		}
+
+		if start.Name.Local == "ErrorResponse" {
+			errResponse := &ErrorResponse{}
+			if err := decoder.DecodeElement(errResponse, &start); err != nil {
+				return decodeError(operation, err)
+			}
+			return errResponse.apiError(operation, http.StatusOK)
+		}
+		if err := decoder.DecodeElement(v, &start); err != nil {
+			return decodeError(operation, err)
+		}
+		return nil
+	}
+}
+
+func decodeError(operation string, err error) error {
+	if errors.Is(err, ErrResponseTooLarge) {
+		return fmt.Errorf("%s: %w", operation, err)
+	}
+	return fmt.Errorf("UNABLE TO UNMARSHAL API RESPONSE: %s", err.Error())
+}
+
+// maxSizeReader fails with ErrResponseTooLarge once more than max bytes are read; max <= 0 means no limit
+type maxSizeReader struct {
+	r    io.Reader
+	max  int64
+	read int64
+}
+
+func (m *maxSizeReader) Read(p []byte) (int, error) {
+	n, err := m.r.Read(p)
+	m.read += int64(n)
+	if m.max > 0 && m.read > m.max {
+		return 0, ErrResponseTooLarge
+	}
+	return n, err
+}
+
+// cappedBuffer keeps the first max bytes written to it and discards the rest
+type cappedBuffer struct {
+	bytes.Buffer
+	max int
+}
+
+func (b *cappedBuffer) Write(p []byte) (int, error) {
+	if room := b.max - b.Len(); room > 0 {
+		if len(p) > room {
+			b.Buffer.Write(p[:room])
+		} else {
+			b.Buffer.Write(p)
+		}
+	}
+	return len(p), nil
+}
+
+// streamReport sends req and returns the response body. The call's span ends when the body is closed.
+// The body is hashed as it is read and verified against its Content-MD5 header at EOF
+func (c *AmazonClient) streamReport(ctx context.Context, req *amazonRequest) (io.ReadCloser, error) {
+	ctx, span := c.startSpan(ctx, req)
+	resp, _, err := c.send(ctx, req)
+	if err != nil {
+		span.end(err)
+		return nil, err
+	}
+	return &reportStream{
+		ctx:    ctx,
+		client: c,
+		op:     req.operation(),
+		body:   resp.Body,
+		header: resp.Header,
+		hash:   md5.New(),
+		span:   span,
+	}, nil
+}
+
+// reportStream is the body of a streamed report
+type reportStream struct {
+	ctx    context.Context
+	client *AmazonClient
+	op     string
+	body   io.ReadCloser
+	header http.Header
+	hash   hash.Hash
+	read   int64
+	span   *callSpan
+	err    error
+	closed bool
+}
+
+func (s *reportStream) Read(p []byte) (int, error) {
+	if s.err != nil {
+		return 0, s.err
+	}
+	n, err := s.body.Read(p)
+	s.hash.Write(p[:n])
+	s.read += int64(n)
+	switch {
+	case err == io.EOF:
+		var sum [md5.Size]byte
+		copy(sum[:], s.hash.Sum(nil))
+		if mismatch := verifyContentMD5(s.op, s.header, sum); mismatch != nil {
+			err = mismatch
+			s.err = err
+		}
+	case err != nil:
+		err = contextError(s.ctx, err)
+		s.err = err
+	}
+	return n, err
+}
+
+// Close closes the body. It returns the *ChecksumMismatchError of a report read to the end
+// that does not match its Content-MD5 header
+func (s *reportStream) Close() error {
+	if s.closed {
+		return nil
+	}
+	s.closed = true
+	err := s.body.Close()
+	if s.client.metrics != nil {
+		s.client.metrics.ObserveDownload(s.op, s.read)
+	}
+	s.span.end(s.err)
+	if errors.Is(s.err, ErrChecksumMismatch) {
+		return s.err
+	}
+	return err
+}
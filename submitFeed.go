@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/xml"
 	"errors"
+	"fmt"
 )
 
 // SubmitFeedRequest holds request data for SubmitFeed call
@@ -29,13 +30,27 @@ type feedHeader struct {
 	MerchantIdentifier string `xml:"MerchantIdentifier"`
 }
 
+// Clone returns a copy of the request that can be modified and executed independently
+func (r *SubmitFeedRequest) Clone() *SubmitFeedRequest {
+	cp := &SubmitFeedRequest{amazonRequest: r.amazonRequest.clone()}
+	if r.feed != nil {
+		f := *r.feed
+		if f.Header != nil {
+			h := *f.Header
+			f.Header = &h
+		}
+		cp.feed = &f
+	}
+	return cp
+}
+
 func (r *SubmitFeedRequest) newFeed(messageType string) *feed {
 	return &feed{
 		Xsi:                       "http://www.w3.org/2001/XMLSchema-instance",
 		NoNamespaceSchemaLocation: "amzn-envelope.xsd",
 		Header: &feedHeader{
-			DocumentVersion:    "1.01",
-			MerchantIdentifier: r.client.credentials.Merchant,
+			DocumentVersion: "1.01",
+			// MerchantIdentifier is set when the feed is submitted
 		},
 		MessageType: messageType,
 	}
@@ -79,18 +94,21 @@ func (f feed) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
 	return e.EncodeToken(start.End())
 }
 
+// Marketplaces selects the marketplaces of the request, which must all share the client's endpoint.
+// By default the client's own marketplace is used
+func (r *SubmitFeedRequest) Marketplaces(marketplaceIDs ...string) *SubmitFeedRequest {
+	r.marketplaces = marketplaceIDs
+	return r
+}
+
 // Do encodes XML feed, calculates MD5 sum, and submits to amazon feedsAPI
 func (r *SubmitFeedRequest) Do(ctx context.Context) (*SubmitFeedResponse, error) {
-	respBytes, err := r.client.submitFeed(ctx, r)
-	if err != nil {
-		return nil, err
-	}
-
 	xmlResponse := &SubmitFeedResponse{}
-	err = xml.Unmarshal(respBytes, xmlResponse)
+	header, err := r.client.submitFeed(ctx, r, xmlResponse)
 	if err != nil {
 		return nil, err
 	}
+	xmlResponse.ResponseMetadata.setHeader(header)
 
 	return xmlResponse, nil
 }
@@ -107,9 +125,7 @@ type SubmitFeedResponse struct {
 			FeedProcessingStatus string `xml:"FeedProcessingStatus"`
 		} `xml:"FeedSubmissionInfo"`
 	} `xml:"SubmitFeedResult"`
-	ResponseMetadata struct {
-		RequestID string `xml:"RequestId"`
-	} `xml:"ResponseMetadata"`
+	ResponseMetadata ResponseMetadata `xml:"ResponseMetadata"`
 }
 
 // OrderAcknowledgements creates XML feed body containing order acknowledgement data
@@ -232,8 +248,9 @@ func (r *SubmitFeedRequest) RevisePriceFeed(revisedItems []*RevisedPriceItem) *S
 			Price: &feedPrice{
 				SKU: item.SKU,
 				StandardPrice: &StandardPrice{
-					Amount:   item.Price,
-					Currency: "USD",
+					Amount: item.Price,
+					// An empty Currency is set from the marketplaces when the feed is submitted
+					Currency: item.Currency,
 				},
 			},
 		}
@@ -246,10 +263,56 @@ func (r *SubmitFeedRequest) RevisePriceFeed(revisedItems []*RevisedPriceItem) *S
 	return r
 }
 
-// RevisedPriceItem request struct for adding revised items to feed messages
+// RevisedPriceItem request struct for adding revised items to feed messages.
+// Currency defaults to the currency of the request's marketplaces, see Marketplaces
 type RevisedPriceItem struct {
-	SKU   string
-	Price float64
+	SKU      string
+	Price    float64
+	Currency string
+}
+
+// priceMessages returns copies of the feed's price messages, with the currency of the request's
+// marketplaces set on prices without one
+func (r *SubmitFeedRequest) priceMessages() ([]*feedPriceMessage, error) {
+	currency, err := r.marketplaceCurrency()
+	if err != nil {
+		return nil, err
+	}
+
+	messages := make([]*feedPriceMessage, len(r.feed.PriceMessages))
+	for i, message := range r.feed.PriceMessages {
+		m := *message
+		if m.Price != nil && m.Price.StandardPrice != nil && m.Price.StandardPrice.Currency == "" {
+			price, standardPrice := *m.Price, *m.Price.StandardPrice
+			standardPrice.Currency = currency
+			price.StandardPrice = &standardPrice
+			m.Price = &price
+		}
+		messages[i] = &m
+	}
+	return messages, nil
+}
+
+// marketplaceCurrency returns the currency of the request's marketplaces, or of the client's marketplace
+// by default. A feed applies to all its marketplaces, so they must share one currency
+func (r *SubmitFeedRequest) marketplaceCurrency() (string, error) {
+	if len(r.marketplaces) == 0 {
+		return r.client.Region.Currency, nil
+	}
+
+	currency := ""
+	for _, id := range r.marketplaces {
+		region, err := RegionByMarketplaceID(id)
+		if err != nil {
+			return "", err
+		}
+		if currency != "" && region.Currency != currency {
+			return "", fmt.Errorf("price feed marketplaces use more than one currency (%s and %s), submit one feed per currency",
+				currency, region.Currency)
+		}
+		currency = region.Currency
+	}
+	return currency, nil
 }
 
 // Price feed structs
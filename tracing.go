@@ -0,0 +1,125 @@
+package amazonmwsapi
+
+import (
+	"context"
+	"time"
+)
+
+// Tracer traces every amzMWS call the client makes, including all its retries.
+// See package mwsotel for OpenTelemetry
+type Tracer interface {
+	// StartCall is called before the first attempt of a call. ctx is the context passed to Do,
+	// DoAll or Download; the returned context is used for the call's requests
+	StartCall(ctx context.Context, call CallInfo) (context.Context, CallSpan)
+}
+
+// CallInfo describes a traced call
+type CallInfo struct {
+	Section        string
+	Operation      string
+	MarketplaceIDs []string
+}
+
+// CallSpan receives the events of a call started by a Tracer
+type CallSpan interface {
+	// Response is called with the status and request ID of every response received
+	Response(status int, requestID string)
+	// Retry is called before the call is sent again after delay, err is the failed attempt's error
+	Retry(attempt int, delay time.Duration, err error)
+	// End is called once the call completed, with err nil on success
+	End(stats CallStats, err error)
+}
+
+// CallStats summarizes the attempts of a call
+type CallStats struct {
+	Retries   int
+	Throttled int
+	// ThrottleWait is the total time spent waiting for the rate limiter
+	ThrottleWait time.Duration
+}
+
+// WithTracer traces every call with t
+func WithTracer(t Tracer) Option {
+	return func(c *AmazonClient) {
+		c.tracer = t
+	}
+}
+
+// callSpan counts the attempts of a traced amzMWS call and reports them to its CallSpan
+type callSpan struct {
+	span         CallSpan
+	attempts     int
+	throttled    int
+	throttleWait time.Duration
+}
+
+type callSpanKey struct{}
+
+// startSpan starts the span of a call to req, if tracing is enabled
+func (c *AmazonClient) startSpan(ctx context.Context, req *amazonRequest) (context.Context, *callSpan) {
+	if c.tracer == nil {
+		return ctx, nil
+	}
+	marketplaces := req.marketplaces
+	if len(marketplaces) == 0 {
+		marketplaces = []string{c.Region.MarketPlaceID}
+	}
+
+	ctx, span := c.tracer.StartCall(ctx, CallInfo{
+		Section:        req.section,
+		Operation:      req.operation(),
+		MarketplaceIDs: marketplaces,
+	})
+	s := &callSpan{span: span}
+	return context.WithValue(ctx, callSpanKey{}, s), s
+}
+
+// spanFromContext returns the call span started by startSpan, or nil
+func spanFromContext(ctx context.Context) *callSpan {
+	s, _ := ctx.Value(callSpanKey{}).(*callSpan)
+	return s
+}
+
+// attempt records an attempt to send the request, and its rate limiter wait
+func (s *callSpan) attempt(wait time.Duration) {
+	if s == nil {
+		return
+	}
+	s.attempts++
+	s.throttleWait += wait
+}
+
+// response records the status and request ID of an attempt's response
+func (s *callSpan) response(status int, requestID string, err error) {
+	if s == nil {
+		return
+	}
+	s.span.Response(status, requestID)
+	if IsThrottled(err) {
+		s.throttled++
+	}
+}
+
+// retry records a retry of the call after delay
+func (s *callSpan) retry(delay time.Duration, err error) {
+	if s == nil {
+		return
+	}
+	s.span.Retry(s.attempts, delay, err)
+}
+
+// end ends the span, recording err as its status
+func (s *callSpan) end(err error) {
+	if s == nil {
+		return
+	}
+	retries := s.attempts - 1
+	if retries < 0 {
+		retries = 0
+	}
+	s.span.End(CallStats{
+		Retries:      retries,
+		Throttled:    s.throttled,
+		ThrottleWait: s.throttleWait,
+	}, err)
+}
@@ -11,24 +11,25 @@ type UpdateReportAcknowledgementsRequest struct {
 	amazonRequest
 }
 
+// Clone returns a copy of the request that can be modified and executed independently
+func (r *UpdateReportAcknowledgementsRequest) Clone() *UpdateReportAcknowledgementsRequest {
+	return &UpdateReportAcknowledgementsRequest{r.amazonRequest.clone()}
+}
+
 // Acknowledged adds ack param to request
 func (r *UpdateReportAcknowledgementsRequest) Acknowledged(ack bool) *UpdateReportAcknowledgementsRequest {
-	r.params.Add("Acknowledged", strconv.FormatBool(ack))
+	r.params.Set("Acknowledged", strconv.FormatBool(ack))
 	return r
 }
 
 // Do sends request to amazonMWS reports API
 func (r *UpdateReportAcknowledgementsRequest) Do(ctx context.Context) (*UpdateReportAcknowledgementsResponse, error) {
-	respBytes, err := r.client.callAPI(ctx, &r.amazonRequest)
-	if err != nil {
-		return nil, err
-	}
-
 	xmlResponse := &UpdateReportAcknowledgementsResponse{}
-	err = xml.Unmarshal(respBytes, xmlResponse)
+	header, err := r.client.callAPI(ctx, &r.amazonRequest, xmlResponse)
 	if err != nil {
-		return nil, r.client.parseAPIerrors(respBytes)
+		return nil, err
 	}
+	xmlResponse.ResponseMetadata.setHeader(header)
 
 	return xmlResponse, nil
 }
@@ -41,7 +42,5 @@ type UpdateReportAcknowledgementsResponse struct {
 		Count      string       `xml:"Count"`
 		ReportInfo []ReportInfo `xml:"ReportInfo"`
 	} `xml:"UpdateReportAcknowledgementsResult"`
-	ResponseMetadata struct {
-		RequestID string `xml:"RequestId"`
-	} `xml:"ResponseMetadata"`
+	ResponseMetadata ResponseMetadata `xml:"ResponseMetadata"`
 }
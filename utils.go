@@ -45,7 +45,7 @@ func XMLTimestamp(t time.Time) string {
 // CanonicalizedQueryString escapes querystring characters according to amzMWS documentation
 func CanonicalizedQueryString(values url.Values) (str string) {
 	// per aws docs and docs for values.Encode, we respect RFC 3986
-	// we may not deal with utf-8, only ascii
+	// utf-8 is percent-encoded byte by byte (see signer_test.go)
 	// params are sorted
 	// we have to fix the '+' to '%20'
 	str = values.Encode()
@@ -59,35 +59,3 @@ func Sign(str string, key []byte) string {
 	mac.Write([]byte(str))
 	return base64.StdEncoding.EncodeToString(mac.Sum(nil))
 }
-
-// Region holds data about the target marketplace website
-type Region struct {
-	RegionID      string
-	Country       string
-	Endpoint      string
-	MarketPlaceID string
-}
-
-// RegionByCountry returns Region data for a specified country
-func RegionByCountry(country string) Region {
-	for _, region := range Regions {
-		if strings.EqualFold(region.Country, country) {
-			return region
-		}
-	}
-	panic("Invalid region, check your data")
-}
-
-// Regions is holding pre-loaded marketplace data
-var Regions = []Region{
-	{"NA", "US", "https://mws.amazonservices.com/", "ATVPDKIKX0DER"},
-	{"NA", "CA", "https://mws.amazonservices.ca/", "A2EUQ1WTGCTBG2"},
-	{"EU", "DE", "https://mws-eu.amazonservices.com/", "A1PA6795UKMFR9"},
-	{"EU", "ES", "https://mws-eu.amazonservices.com/", "A1RKKUPIHCS9HS"},
-	{"EU", "FR", "https://mws-eu.amazonservices.com/", "A13V1IB3VIYZZH"},
-	{"EU", "IN", "https://mws.amazonservices.in/", "A21TJRUUN4KGV"},
-	{"EU", "IT", "https://mws-eu.amazonservices.com/", "APJ6JRA9NG5V4"},
-	{"EU", "UK", "https://mws-eu.amazonservices.com/", "A1F83G8C2ARO7P"},
-	{"FE", "JP", "https://mws.amazonservices.jp/", "A1VC38T7YXB528"},
-	{"CN", "CN", "https://mws.amazonservices.com.cn/", "AAHKV2X7AFYLW"},
-}